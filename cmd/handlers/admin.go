@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/pringleskate/tp_db_forum/internal/models"
+	"github.com/valyala/fasthttp"
+)
+
+func (h handler) AdminSetRole(c *fasthttp.RequestCtx) {
+	input := &models.RoleChange{}
+	if !h.decode(c, input) || !h.valid(c, input.Validate()) {
+		return
+	}
+
+	nickname := c.UserValue("nickname").(string)
+	err := h.Service.SetRole(requestContext(c), nickname, input.Role, currentUser(c))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	response, _ := input.MarshalJSON()
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+	return
+}
+
+func (h handler) AdminGrantModerator(c *fasthttp.RequestCtx) {
+	moderators, err := h.Service.GrantModerator(requestContext(c), c.UserValue("slug").(string), c.UserValue("nickname").(string), currentUser(c))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	response, _ := json.Marshal(moderators)
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+	return
+}
+
+func (h handler) AdminRevokeModerator(c *fasthttp.RequestCtx) {
+	moderators, err := h.Service.RevokeModerator(requestContext(c), c.UserValue("slug").(string), c.UserValue("nickname").(string), currentUser(c))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	response, _ := json.Marshal(moderators)
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+	return
+}
+
+/* reports the drifted counters, ?fix=true also corrects them */
+func (h handler) AdminReconcileCounters(c *fasthttp.RequestCtx) {
+	result, err := h.Service.ReconcileCounters(requestContext(c), getBool("fix", c.QueryArgs()), currentUser(c))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	response, _ := result.MarshalJSON()
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+	return
+}
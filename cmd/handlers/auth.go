@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/pringleskate/tp_db_forum/internal/models"
+	"github.com/valyala/fasthttp"
+)
+
+/* user value the middleware stores the authenticated nickname under */
+const userKey = "auth_user"
+
+var bearer = []byte("Bearer ")
+
+/* lets next run only for requests with a valid Authorization: Bearer <token> header */
+func (h handler) Authenticated(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(c *fasthttp.RequestCtx) {
+		nickname, err := h.Service.Authenticate(requestContext(c), bearerToken(c))
+		if err != nil {
+			status, respErr, _ := h.ConvertError(err)
+			if status == fasthttp.StatusUnauthorized {
+				c.Response.Header.Set("WWW-Authenticate", "Bearer")
+			}
+			h.WriteResponse(c, status, respErr)
+			return
+		}
+
+		c.SetUserValue(userKey, nickname)
+		next(c)
+	}
+}
+
+/* like Authenticated, but requests without an Authorization header reach next anonymously */
+func (h handler) Identified(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	authenticated := h.Authenticated(next)
+	return func(c *fasthttp.RequestCtx) {
+		if len(c.Request.Header.Peek("Authorization")) == 0 {
+			next(c)
+			return
+		}
+		authenticated(c)
+	}
+}
+
+func bearerToken(c *fasthttp.RequestCtx) string {
+	header := c.Request.Header.Peek("Authorization")
+	if !bytes.HasPrefix(header, bearer) {
+		return ""
+	}
+	return string(bytes.TrimSpace(header[len(bearer):]))
+}
+
+/* empty outside of Authenticated routes and for anonymous Identified requests */
+func currentUser(c *fasthttp.RequestCtx) string {
+	nickname, _ := c.UserValue(userKey).(string)
+	return nickname
+}
+
+func (h handler) Login(c *fasthttp.RequestCtx) {
+	credentials := &models.Credentials{}
+	if !h.decode(c, credentials) || !h.valid(c, credentials.Validate()) {
+		return
+	}
+
+	session, err := h.Service.Login(requestContext(c), *credentials)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	response, _ := json.Marshal(session)
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+	return
+}
+
+func (h handler) Logout(c *fasthttp.RequestCtx) {
+	err := h.Service.Logout(requestContext(c), bearerToken(c))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	h.WriteResponse(c, fasthttp.StatusOK, []byte("{}"))
+	return
+}
+
+func (h handler) UserPassword(c *fasthttp.RequestCtx) {
+	input := &models.PasswordChange{}
+	if !h.decode(c, input) || !h.valid(c, input.Validate()) {
+		return
+	}
+
+	err := h.Service.ChangePassword(requestContext(c), c.UserValue("nickname").(string), input.Password, currentUser(c))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	h.WriteResponse(c, fasthttp.StatusOK, []byte("{}"))
+	return
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"context"
+	"github.com/valyala/fasthttp"
+	"time"
+)
+
+/*
+Bounds the request context set up by Logged, so queries still running when the time is up
+are cancelled in postgres and the handler answers 504. Has to sit inside Logged.
+A timeout of 0 leaves the request unbounded.
+*/
+func Deadline(timeout time.Duration, next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	if timeout <= 0 {
+		return next
+	}
+	return func(c *fasthttp.RequestCtx) {
+		ctx, cancel := context.WithTimeout(requestContext(c), timeout)
+		defer cancel()
+
+		c.SetUserValue(contextKey, ctx)
+		next(c)
+	}
+}
@@ -4,19 +4,16 @@ import (
 	"encoding/json"
 	"github.com/pringleskate/tp_db_forum/internal/models"
 	"github.com/valyala/fasthttp"
-	"log"
 )
 
 
 func (h handler) ForumCreate(c *fasthttp.RequestCtx) {
 	forumInput := &models.ForumCreate{}
-	err := forumInput.UnmarshalJSON(c.PostBody())
-	if err != nil {
-		log.Println(err)
+	if !h.decode(c, forumInput) || !h.valid(c, forumInput.Validate()) {
 		return
 	}
 
-	forum, err := h.Service.CreateForum(*forumInput)
+	forum, err := h.Service.CreateForum(requestContext(c), *forumInput)
 	if err != nil {
 		status, respErr, _ := h.ConvertError(err)
 		if status == fasthttp.StatusConflict {
@@ -36,10 +33,9 @@ func (h handler) ForumCreate(c *fasthttp.RequestCtx) {
 func (h handler) ForumGet(c *fasthttp.RequestCtx) {
 	forumInput := models.ForumInput{}
 	forumInput.Slug = c.UserValue("slug").(string)
-	forum, err := h.Service.GetForum(forumInput)
+	forum, err := h.Service.GetForum(requestContext(c), forumInput)
 	if err != nil {
-		status, respErr, _ := h.ConvertError(err)
-		h.WriteResponse(c, status, respErr)
+		h.writeError(c, err)
 		return
 	}
 
@@ -54,12 +50,12 @@ func (h handler) ForumGetThreads(c *fasthttp.RequestCtx) {
 		Limit: c.QueryArgs().GetUintOrZero("limit"),
 		Since: string(c.QueryArgs().Peek("since")),
 		Desc:  getBool("desc", c.QueryArgs()),
+		Archived: getBool("archived", c.QueryArgs()),
 	}
 
-	threads, err := h.Service.GetForumThreads(input)
+	threads, err := h.Service.GetForumThreads(requestContext(c), input)
 	if err != nil {
-		status, respErr, _ := h.ConvertError(err)
-		h.WriteResponse(c, status, respErr)
+		h.writeError(c, err)
 		return
 	}
 
@@ -77,10 +73,9 @@ func (h handler) ForumGetUsers(c *fasthttp.RequestCtx) {
 		Desc:  getBool("desc", c.QueryArgs()),
 	}
 
-	users, err := h.Service.GetForumUsers(input)
+	users, err := h.Service.GetForumUsers(requestContext(c), input)
 	if err != nil {
-		status, respErr, _ := h.ConvertError(err)
-		h.WriteResponse(c, status, respErr)
+		h.writeError(c, err)
 		return
 	}
 
@@ -89,3 +84,37 @@ func (h handler) ForumGetUsers(c *fasthttp.RequestCtx) {
 	h.WriteResponse(c, fasthttp.StatusOK, response)
 	return
 }
+
+func (h handler) ForumList(c *fasthttp.RequestCtx) {
+	input := models.ForumList{
+		Limit: c.QueryArgs().GetUintOrZero("limit"),
+		Since: string(c.QueryArgs().Peek("since")),
+		Sort:  string(c.QueryArgs().Peek("sort")),
+		Desc:  getBool("desc", c.QueryArgs()),
+		Title: string(c.QueryArgs().Peek("title")),
+	}
+
+	forums, err := h.Service.GetForums(requestContext(c), input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	response, _ := json.Marshal(forums)
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+	return
+}
+
+func (h handler) ForumModerators(c *fasthttp.RequestCtx) {
+	moderators, err := h.Service.GetModerators(requestContext(c), c.UserValue("slug").(string))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	response, _ := json.Marshal(moderators)
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+	return
+}
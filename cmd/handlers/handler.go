@@ -1,8 +1,9 @@
 package handlers
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
+	"github.com/jackc/pgx"
 	"github.com/pringleskate/tp_db_forum/internal/models"
 	"github.com/pringleskate/tp_db_forum/internal/services"
 	"github.com/pringleskate/tp_db_forum/internal/storages/forumStorage"
@@ -18,22 +19,48 @@ type Handler interface {
 	ForumGet(c *fasthttp.RequestCtx)
 	ForumGetThreads(c *fasthttp.RequestCtx)
 	ForumGetUsers(c *fasthttp.RequestCtx)
+	ForumList(c *fasthttp.RequestCtx)
+	ForumModerators(c *fasthttp.RequestCtx)
 
 	ThreadCreate(c *fasthttp.RequestCtx)
 	ThreadVote(c *fasthttp.RequestCtx)
+	ThreadUnvote(c *fasthttp.RequestCtx)
+	ThreadGetVotes(c *fasthttp.RequestCtx)
 	ThreadGet(c *fasthttp.RequestCtx)
 	ThreadUpdate(c *fasthttp.RequestCtx)
 	ThreadGetPosts(c *fasthttp.RequestCtx)
+	ThreadDelete(c *fasthttp.RequestCtx)
 
 	PostsCreate(c *fasthttp.RequestCtx)
 	PostGet(c *fasthttp.RequestCtx)
 	PostUpdate(c *fasthttp.RequestCtx)
+	PostDelete(c *fasthttp.RequestCtx)
+	PostVote(c *fasthttp.RequestCtx)
+	PostUnvote(c *fasthttp.RequestCtx)
+	PostRevisions(c *fasthttp.RequestCtx)
+	PostRevisionsDiff(c *fasthttp.RequestCtx)
 
 	UserCreate(c *fasthttp.RequestCtx)
 	UserGet(c *fasthttp.RequestCtx)
 	UserUpdate(c *fasthttp.RequestCtx)
+	UserGetVotes(c *fasthttp.RequestCtx)
+
+	Search(c *fasthttp.RequestCtx)
+
+	Login(c *fasthttp.RequestCtx)
+	Logout(c *fasthttp.RequestCtx)
+	UserPassword(c *fasthttp.RequestCtx)
+
+	AdminSetRole(c *fasthttp.RequestCtx)
+	AdminGrantModerator(c *fasthttp.RequestCtx)
+	AdminRevokeModerator(c *fasthttp.RequestCtx)
+	AdminReconcileCounters(c *fasthttp.RequestCtx)
+	Authenticated(next fasthttp.RequestHandler) fasthttp.RequestHandler
+	Identified(next fasthttp.RequestHandler) fasthttp.RequestHandler
 
 	Clear(c *fasthttp.RequestCtx)
+	Healthz(c *fasthttp.RequestCtx)
+	Readyz(c *fasthttp.RequestCtx)
 	Status(c *fasthttp.RequestCtx)
 }
 /*
@@ -72,20 +99,72 @@ func (h handler) WriteResponse(c *fasthttp.RequestCtx, status int, body []byte)
 	c.Write(body)
 }
 
-func (h handler) ConvertError(someError error) (status int, body []byte, err error) {
-	Error, ok := someError.(models.Error)
-	if !ok {
-		return status, body, errors.New("it is not server error")
+/* 400 for bodies that do not decode, callers just return when it reports false */
+func (h handler) decode(c *fasthttp.RequestCtx, input interface{ UnmarshalJSON([]byte) error }) bool {
+	if err := input.UnmarshalJSON(c.PostBody()); err != nil {
+		requestLogger(c).Debug("malformed request body", "err", err)
+		h.writeError(c, models.MalformedBody(err))
+		return false
 	}
+	return true
+}
 
-	body, err = json.Marshal(Error)
+/* takes the result of a Validate method, 400 with the field errors when it failed */
+func (h handler) valid(c *fasthttp.RequestCtx, err error) bool {
 	if err != nil {
-		return status, body, err
+		h.writeError(c, err)
+		return false
+	}
+	return true
+}
+
+/* writes the error response, causes of internal errors only go to the log */
+func (h handler) writeError(c *fasthttp.RequestCtx, err error) {
+	status, respErr, _ := h.ConvertError(err)
+	switch status {
+	case fasthttp.StatusInternalServerError:
+		requestLogger(c).Error("request failed", "err", err)
+	case fasthttp.StatusGatewayTimeout, fasthttp.StatusServiceUnavailable:
+		requestLogger(c).Warn("request aborted", "err", err)
+	}
+	h.WriteResponse(c, status, respErr)
+}
+
+var kindStatus = map[models.Kind]int{
+	models.KindInternal:     fasthttp.StatusInternalServerError,
+	models.KindValidation:   fasthttp.StatusBadRequest,
+	models.KindUnauthorized: fasthttp.StatusUnauthorized,
+	models.KindForbidden:    fasthttp.StatusForbidden,
+	models.KindNotFound:     fasthttp.StatusNotFound,
+	models.KindConflict:     fasthttp.StatusConflict,
+	models.KindTimeout:      fasthttp.StatusGatewayTimeout,
+	models.KindUnavailable:  fasthttp.StatusServiceUnavailable,
+}
+
+/*
+	Maps an error to the response status and body. Anything that is not a models.Error
+	(or wraps one) is answered as a plain 500 so the client never sees a status of 0,
+	the returned error is only set when the body could not be marshalled.
+*/
+func (h handler) ConvertError(someError error) (status int, body []byte, err error) {
+	var Error models.Error
+	switch {
+	case errors.Is(someError, context.DeadlineExceeded):
+		Error = models.ErrTimeout
+	case errors.Is(someError, context.Canceled), errors.Is(someError, pgx.ErrAcquireTimeout):
+		Error = models.ErrUnavailable
+	case !errors.As(someError, &Error):
+		Error = models.ErrInternal
+	}
+
+	status, ok := kindStatus[Error.Kind]
+	if !ok {
+		status, Error = fasthttp.StatusInternalServerError, models.ErrInternal
 	}
 
-	status, err = strconv.Atoi(Error.Code)
+	body, err = Error.MarshalJSON()
 	if err != nil {
-		return status, body, err
+		return fasthttp.StatusInternalServerError, []byte(`{"code":"internal","message":"internal error"}`), err
 	}
 
 	return status, body, nil
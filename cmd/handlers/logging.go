@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/pringleskate/tp_db_forum/internal/logger"
+	"github.com/valyala/fasthttp"
+	"time"
+)
+
+/* user value the request context is stored under, services and storages get it as their ctx */
+const contextKey = "request_ctx"
+
+const requestIDHeader = "X-Request-ID"
+
+/* longer or non-printable client IDs are replaced, they end up in every log line of the request */
+const maxRequestIDLength = 128
+
+/*
+Outermost per-route middleware: picks up X-Request-ID or makes one up, echoes it back,
+hands the rest of the request a logger carrying it and writes one access log line when done.
+*/
+func Logged(log *logger.Logger, route string, next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(c *fasthttp.RequestCtx) {
+		start := time.Now()
+
+		id := string(c.Request.Header.Peek(requestIDHeader))
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		c.Response.Header.Set(requestIDHeader, id)
+
+		ctx, cancel := newRequestContext(c)
+		defer cancel()
+
+		requestLog := log.With("request_id", id)
+		c.SetUserValue(contextKey, logger.NewContext(ctx, requestLog))
+
+		next(c)
+
+		fields := []interface{}{
+			"method", string(c.Method()),
+			"route", route,
+			"path", string(c.Path()),
+			"status", c.Response.StatusCode(),
+			"latency_ms", float64(time.Since(start).Microseconds()) / 1000,
+			"bytes", len(c.Response.Body()),
+			"remote_addr", c.RemoteIP().String(),
+		}
+		if user := currentUser(c); user != "" {
+			fields = append(fields, "user", user)
+		}
+		requestLog.Info("request", fields...)
+	}
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	id := make([]byte, 16)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+/*
+Cancelled when the server shuts down, which fasthttp signals by closing RequestCtx.Done.
+fasthttp does not notice clients that hang up, so a disconnect cancels nothing,
+the request runs on until it is done or its Deadline passes.
+Not derived from c itself: c is recycled once the handler returns.
+*/
+func newRequestContext(c *fasthttp.RequestCtx) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	shutdown := c.Done()
+	go func() {
+		select {
+		case <-shutdown:
+		case <-ctx.Done():
+		}
+		cancel()
+	}()
+	return ctx, cancel
+}
+
+/* the context set up by Logged, c itself for requests that bypassed it */
+func requestContext(c *fasthttp.RequestCtx) context.Context {
+	if ctx, ok := c.UserValue(contextKey).(context.Context); ok {
+		return ctx
+	}
+	return c
+}
+
+func requestLogger(c *fasthttp.RequestCtx) *logger.Logger {
+	return logger.FromContext(requestContext(c))
+}
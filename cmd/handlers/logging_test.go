@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"github.com/pringleskate/tp_db_forum/internal/logger"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+/* a request still running when the server shuts down sees its context cancelled */
+func TestRequestContextCancelledOnShutdown(t *testing.T) {
+	running := make(chan struct{})
+	server := &fasthttp.Server{Handler: Logged(logger.New(ioutil.Discard, logger.LevelError), "test", func(c *fasthttp.RequestCtx) {
+		close(running)
+		select {
+		case <-requestContext(c).Done():
+			c.SetBodyString(requestContext(c).Err().Error())
+		case <-time.After(5 * time.Second):
+			c.SetBodyString("not cancelled")
+		}
+	})}
+
+	ln := fasthttputil.NewInmemoryListener()
+	go server.Serve(ln)
+
+	conn, err := ln.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err = conn.Write([]byte("GET / HTTP/1.1\r\nHost: test\r\n\r\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	<-running
+	shutdown := make(chan error, 1)
+	go func() {
+		shutdown <- server.Shutdown()
+	}()
+
+	response := fasthttp.Response{}
+	if err = response.Read(bufio.NewReader(conn)); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(response.Body()); got != context.Canceled.Error() {
+		t.Errorf("got %q, want %q", got, context.Canceled.Error())
+	}
+	if err = <-shutdown; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRequestContextOutsideLogged(t *testing.T) {
+	c := &fasthttp.RequestCtx{}
+	c.SetUserValue("key", "value")
+	if got := requestContext(c).Value("key"); got != "value" {
+		t.Errorf("got %v, want the request's user values", got)
+	}
+}
@@ -2,21 +2,26 @@ package handlers
 
 import (
 	"encoding/json"
-	"fmt"
 	"github.com/pringleskate/tp_db_forum/internal/models"
 	"github.com/valyala/fasthttp"
-	"log"
 	"strconv"
-	"time"
 )
 
-/*
 func (h handler) PostsCreate(c *fasthttp.RequestCtx) {
 	postsInput := make([]models.PostCreate, 0)
 	threadInput := models.ThreadInput{}
 	err := json.Unmarshal(c.PostBody(), &postsInput)
 	if err != nil {
-		log.Println(err)
+		requestLogger(c).Debug("malformed request body", "err", err)
+		h.writeError(c, models.MalformedBody(err))
+		return
+	}
+	for i := range postsInput {
+		if postsInput[i].Author == "" {
+			postsInput[i].Author = currentUser(c)
+		}
+	}
+	if !h.valid(c, models.ValidatePosts(postsInput)) {
 		return
 	}
 
@@ -24,10 +29,9 @@ func (h handler) PostsCreate(c *fasthttp.RequestCtx) {
 	threadInput.ThreadID = slugOrID.ThreadID
 	threadInput.Slug = slugOrID.Slug
 
-	posts, err := h.Service.CreatePosts(postsInput, threadInput)
+	posts, err := h.Service.CreatePosts(requestContext(c), postsInput, threadInput, currentUser(c))
 	if err != nil {
-		status, respErr, _ := h.ConvertError(err)
-		h.WriteResponse(c, status, respErr)
+		h.writeError(c, err)
 		return
 	}
 
@@ -35,214 +39,145 @@ func (h handler) PostsCreate(c *fasthttp.RequestCtx) {
 
 	h.WriteResponse(c, fasthttp.StatusCreated, response)
 	return
-}*/
-/*
-func (h handler) PostsCreate(c *fasthttp.RequestCtx) {
-	postsInput := make([]models.PostCreate, 0)
-	threadInput := models.ThreadInput{}
-	err := json.Unmarshal(c.PostBody(), &postsInput)
-	if err != nil {
-		log.Println(err)
-		return
-	}
-
-
-	slugOrID := SlagOrID(c)
-	threadInput.ThreadID = slugOrID.ThreadID
-	threadInput.Slug = slugOrID.Slug
-
-
-	posts := make([]models.Post, 0)
+}
 
-	forum, err := h.Threads.GetForumByThread(&threadInput)
+func (h handler) PostGet(c *fasthttp.RequestCtx) {
+	id, _ := strconv.Atoi(c.UserValue("id").(string))
+	related := c.QueryArgs().Peek("related")
+	post, err := h.Service.GetPost(requestContext(c), id, string(related))
 	if err != nil {
-
-		status, respErr, _ := h.ConvertError(err)
-		h.WriteResponse(c, status, respErr)
+		h.writeError(c, err)
 		return
-		//return []models.Post{}, err
 	}
 
-	if len(postsInput) == 0 {
-		response, _ := json.Marshal(posts)
-		h.WriteResponse(c, fasthttp.StatusCreated, response)
-		return
-		//return []models.Post{}, nil
-	}
-
-	created := time.Now().Format(time.RFC3339Nano)
-	for _, postInput := range postsInput {
-		post := models.Post{
-			ThreadInput: threadInput,
-			Parent:      postInput.Parent,
-			Author:      postInput.Author,
-			Message:     postInput.Message,
-			Forum:       forum,
-			Created:     created,
-		}
-
-		if post.Parent != 0 {
-			parentThread, err := h.Posts.CheckParentPostThread(post.Parent)
-			if err != nil {
-				fmt.Println(err)
-				status, respErr, _ := h.ConvertError(err)
-				h.WriteResponse(c, status, respErr)
-				return
-				//return []models.Post{}, err
-			}
-
-			if parentThread != post.ThreadID  {
-				status, respErr, _ := h.ConvertError(models.Error{Code:"409"})
-				h.WriteResponse(c, status, respErr)
-				return
-				//return []models.Post{}, models.Error{Code:"409"}
-			}
-		}
-
-		output, err := h.Posts.CreatePost(post)
-		if err != nil {
-			status, respErr, _ := h.ConvertError(err)
-			h.WriteResponse(c, status, respErr)
-			return
-			//return []models.Post{}, err
-		}
+	response, _ := json.Marshal(post)
 
-		posts = append(posts, output)
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+	return
+}
 
-		err = h.Forums.UpdatePostsCount(models.ForumInput{Slug: forum})
-		if err != nil {
-			status, respErr, _ := h.ConvertError(err)
-			h.WriteResponse(c, status, respErr)
-			return
-			//return []models.Post{}, err
-		}
-	}
+func (h handler) PostUpdate(c *fasthttp.RequestCtx) {
+	postInput := &models.PostUpdate{}
+	id, _ := strconv.Atoi(c.UserValue("id").(string))
+	postInput.ID = int(id)
 
-	userID, err := h.Users.GetUserIDByNickname(postsInput[0].Author)
-	if err != nil {
-		status, respErr, _ := h.ConvertError(err)
-		h.WriteResponse(c, status, respErr)
+	if !h.decode(c, postInput) || !h.valid(c, postInput.Validate()) {
 		return
-		//return []models.Post{}, err
 	}
 
-	forumID, err := h.Forums.GetForumID(models.ForumInput{Slug: forum})
+	post, err := h.Service.UpdatePost(requestContext(c), *postInput, currentUser(c))
 	if err != nil {
-		status, respErr, _ := h.ConvertError(err)
-		h.WriteResponse(c, status, respErr)
+		h.writeError(c, err)
 		return
-		//return []models.Post{}, err
 	}
 
-	err = h.Forums.AddUserToForum(userID, forumID)
-	if err != nil && err.Error() != "409" {
-		status, respErr, _ := h.ConvertError(err)
-		h.WriteResponse(c, status, respErr)
-		return
-		//return []models.Post{}, err
-	}
-
-	//	return posts, nil
-
-	response, _ := json.Marshal(posts)
+	response, _ := json.Marshal(post)
 
-	h.WriteResponse(c, fasthttp.StatusCreated, response)
+	h.WriteResponse(c, fasthttp.StatusOK, response)
 	return
-}*/
+}
 
-func (h handler) PostsCreate(c *fasthttp.RequestCtx) {
-	postsInput := make([]models.PostCreate, 0)
-	threadInput := models.ThreadInput{}
-	err := json.Unmarshal(c.PostBody(), &postsInput)
+func (h handler) PostDelete(c *fasthttp.RequestCtx) {
+	id, _ := strconv.Atoi(c.UserValue("id").(string))
+
+	post, err := h.Service.DeletePost(requestContext(c), id, currentUser(c))
 	if err != nil {
-		log.Println(err)
+		h.writeError(c, err)
 		return
 	}
 
-	slugOrID := SlagOrID(c)
-	threadInput.ThreadID = slugOrID.ThreadID
-	threadInput.Slug = slugOrID.Slug
+	response, _ := json.Marshal(post)
 
-	posts := make([]models.Post, 0)
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+	return
+}
 
-	forum, err := h.Threads.GetForumByThread(&threadInput)
-	if err != nil {
-		status, respErr, _ := h.ConvertError(err)
-		h.WriteResponse(c, status, respErr)
-		return
-	}
+func (h handler) PostVote(c *fasthttp.RequestCtx) {
+	voteInput := &models.Vote{}
 
-	if len(postsInput) == 0 {
-		response, _ := json.Marshal(posts)
-		h.WriteResponse(c, fasthttp.StatusCreated, response)
+	if !h.decode(c, voteInput) || !h.valid(c, voteInput.Validate()) {
 		return
 	}
 
-	created := time.Now().Format(time.RFC3339Nano)
-	posts, err = h.Posts.CreatePosts(threadInput, forum, created, postsInput)
+	voteInput.Post, _ = strconv.Atoi(c.UserValue("id").(string))
+
+	post, err := h.Service.PostVote(requestContext(c), *voteInput, currentUser(c))
 	if err != nil {
-		if err.Error() == "404" {
-			fmt.Println(err)
-			status, respErr, _ := h.ConvertError(err)
-			h.WriteResponse(c, status, respErr)
-			return
-		}
-		fmt.Println(err)
-		status, respErr, _ := h.ConvertError(err)
-		h.WriteResponse(c, status, respErr)
+		h.writeError(c, err)
 		return
 	}
 
-	err = h.Forums.UpdatePostsCount(models.ForumInput{Slug: forum}, len(posts))
+	response, _ := json.Marshal(post)
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+	return
+}
+
+func (h handler) PostUnvote(c *fasthttp.RequestCtx) {
+	voteInput := models.Vote{}
+	voteInput.Post, _ = strconv.Atoi(c.UserValue("id").(string))
+
+	post, err := h.Service.PostUnvote(requestContext(c), voteInput, currentUser(c))
 	if err != nil {
-		status, respErr, _ := h.ConvertError(err)
-		h.WriteResponse(c, status, respErr)
+		h.writeError(c, err)
 		return
 	}
 
-	response, _ := json.Marshal(posts)
+	response, _ := json.Marshal(post)
 
-	h.WriteResponse(c, fasthttp.StatusCreated, response)
+	h.WriteResponse(c, fasthttp.StatusOK, response)
 	return
 }
 
-func (h handler) PostGet(c *fasthttp.RequestCtx) {
+func (h handler) PostRevisions(c *fasthttp.RequestCtx) {
 	id, _ := strconv.Atoi(c.UserValue("id").(string))
-	related := c.QueryArgs().Peek("related")
-	post, err := h.Service.GetPost(id, string(related))
+
+	revisions, err := h.Service.GetPostRevisions(requestContext(c), id, currentUser(c))
 	if err != nil {
-		status, respErr, _ := h.ConvertError(err)
-		h.WriteResponse(c, status, respErr)
+		h.writeError(c, err)
 		return
 	}
 
-	response, _ := json.Marshal(post)
+	response, _ := json.Marshal(revisions)
 
 	h.WriteResponse(c, fasthttp.StatusOK, response)
 	return
 }
 
-func (h handler) PostUpdate(c *fasthttp.RequestCtx) {
-	postInput := &models.PostUpdate{}
+/* ?from=&to= pick the revisions, by default the latest one is compared with the one before it */
+func (h handler) PostRevisionsDiff(c *fasthttp.RequestCtx) {
 	id, _ := strconv.Atoi(c.UserValue("id").(string))
-	postInput.ID = int(id)
 
-	err := postInput.UnmarshalJSON(c.PostBody())
-	if err != nil {
-		log.Println(err)
+	from, ok := h.revisionArg(c, "from")
+	if !ok {
+		return
+	}
+	to, ok := h.revisionArg(c, "to")
+	if !ok {
 		return
 	}
 
-	post, err := h.Service.UpdatePost(*postInput)
+	diff, err := h.Service.DiffPostRevisions(requestContext(c), id, from, to, currentUser(c))
 	if err != nil {
-		status, respErr, _ := h.ConvertError(err)
-		h.WriteResponse(c, status, respErr)
+		h.writeError(c, err)
 		return
 	}
 
-	response, _ := json.Marshal(post)
+	response, _ := diff.MarshalJSON()
 
 	h.WriteResponse(c, fasthttp.StatusOK, response)
 	return
 }
 
+/* 0 when the argument is absent, 400 when it is not a revision number */
+func (h handler) revisionArg(c *fasthttp.RequestCtx, name string) (int, bool) {
+	if !c.QueryArgs().Has(name) {
+		return 0, true
+	}
+	n, err := c.QueryArgs().GetUint(name)
+	if err != nil || n == 0 {
+		h.writeError(c, models.ErrValidation.WithMessage(name+" must be a revision number"))
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/pringleskate/tp_db_forum/internal/models"
+	"github.com/valyala/fasthttp"
+	"time"
+)
+
+func (h handler) Search(c *fasthttp.RequestCtx) {
+	input := models.SearchInput{
+		Query:   string(c.QueryArgs().Peek("q")),
+		Forum:   string(c.QueryArgs().Peek("forum")),
+		Author:  string(c.QueryArgs().Peek("author")),
+		Limit:   c.QueryArgs().GetUintOrZero("limit"),
+		Related: string(c.QueryArgs().Peek("related")),
+	}
+
+	if since := c.QueryArgs().Peek("since"); len(since) != 0 {
+		var err error
+		input.Since, err = time.Parse(time.RFC3339Nano, string(since))
+		if err != nil {
+			h.writeError(c, models.ErrValidation.WithMessage("since must be an RFC 3339 time"))
+			return
+		}
+	}
+
+	results, err := h.Service.Search(requestContext(c), input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	response, _ := json.Marshal(results)
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+	return
+}
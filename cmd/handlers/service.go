@@ -2,11 +2,28 @@ package handlers
 
 import (
 	"encoding/json"
+	"github.com/pringleskate/tp_db_forum/internal/models"
 	"github.com/valyala/fasthttp"
 )
 
+/* an unconfirmed call answers 202 with the token the confirming call has to pass as ?confirm= */
 func (h handler) Clear(c *fasthttp.RequestCtx) {
-	h.Service.Clear()
+	input := models.ClearInput{
+		Forum:   string(c.QueryArgs().Peek("forum")),
+		Confirm: string(c.QueryArgs().Peek("confirm")),
+	}
+
+	confirmation, err := h.Service.Clear(requestContext(c), input, currentUser(c))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	if confirmation.Confirm != "" {
+		response, _ := confirmation.MarshalJSON()
+		h.WriteResponse(c, fasthttp.StatusAccepted, response)
+		return
+	}
 
 	c.SetContentType("application/json")
 	c.SetStatusCode(fasthttp.StatusOK)
@@ -14,7 +31,11 @@ func (h handler) Clear(c *fasthttp.RequestCtx) {
 }
 
 func (h handler) Status(c *fasthttp.RequestCtx) {
-	status := h.Service.Status()
+	status, err := h.Service.Status(requestContext(c))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
 
 	response, _ := json.Marshal(status)
 
@@ -22,3 +43,25 @@ func (h handler) Status(c *fasthttp.RequestCtx) {
 	return
 }
 
+/* liveness: answers as long as the process serves requests, touches no dependency */
+func (h handler) Healthz(c *fasthttp.RequestCtx) {
+	response, _ := models.Health{Status: models.HealthOK}.MarshalJSON()
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+	return
+}
+
+/* readiness: 503 with the failed checks while the storage cannot serve requests */
+func (h handler) Readyz(c *fasthttp.RequestCtx) {
+	health := h.Service.Ready(requestContext(c))
+
+	response, _ := health.MarshalJSON()
+
+	if health.Status != models.HealthOK {
+		h.WriteResponse(c, fasthttp.StatusServiceUnavailable, response)
+		return
+	}
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+	return
+}
+
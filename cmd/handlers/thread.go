@@ -2,25 +2,26 @@ package handlers
 
 import (
 	"encoding/json"
-	"fmt"
 	"github.com/pringleskate/tp_db_forum/internal/models"
 	"github.com/valyala/fasthttp"
-	"log"
 )
 
 func (h handler) ThreadCreate(c *fasthttp.RequestCtx) {
 	threadInput := &models.Thread{}
-	err :=threadInput.UnmarshalJSON(c.PostBody())
-	if err != nil {
-		log.Println(err)
+	if !h.decode(c, threadInput) {
+		return
+	}
+	if threadInput.Author == "" {
+		threadInput.Author = currentUser(c)
+	}
+	if !h.valid(c, threadInput.Validate()) {
 		return
 	}
 
 	threadInput.Forum = c.UserValue("slug").(string)
 
-	thread, err := h.Service.CreateThread(*threadInput)
+	thread, err := h.Service.CreateThread(requestContext(c), *threadInput, currentUser(c))
 	if err != nil {
-		fmt.Println(err)
 		status, respErr, _ := h.ConvertError(err)
 		if status == fasthttp.StatusConflict {
 			response, _ := thread.MarshalJSON()
@@ -40,18 +41,30 @@ func (h handler) ThreadCreate(c *fasthttp.RequestCtx) {
 func (h handler) ThreadVote(c *fasthttp.RequestCtx) {
 	voteInput := &models.Vote{}
 
-	err := voteInput.UnmarshalJSON(c.PostBody())
-	if err != nil {
-		log.Println(err)
+	if !h.decode(c, voteInput) || !h.valid(c, voteInput.Validate()) {
 		return
 	}
 
 	voteInput.Thread = SlagOrID(c)
 
-	thread, err := h.Service.ThreadVote(*voteInput)
+	thread, err := h.Service.ThreadVote(requestContext(c), *voteInput, currentUser(c))
 	if err != nil {
-		status, respErr, _ := h.ConvertError(err)
-		h.WriteResponse(c, status, respErr)
+		h.writeError(c, err)
+		return
+	}
+
+	response, _ := json.Marshal(thread)
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+	return
+}
+
+func (h handler) ThreadUnvote(c *fasthttp.RequestCtx) {
+	voteInput := models.Vote{Thread: SlagOrID(c)}
+
+	thread, err := h.Service.ThreadUnvote(requestContext(c), voteInput, currentUser(c))
+	if err != nil {
+		h.writeError(c, err)
 		return
 	}
 
@@ -61,13 +74,32 @@ func (h handler) ThreadVote(c *fasthttp.RequestCtx) {
 	return
 }
 
+func (h handler) ThreadGetVotes(c *fasthttp.RequestCtx) {
+	input := models.ThreadGetVotes{
+		ThreadInput: SlagOrID(c),
+		Limit:       c.QueryArgs().GetUintOrZero("limit"),
+		Since:       string(c.QueryArgs().Peek("since")),
+		Desc:        getBool("desc", c.QueryArgs()),
+	}
+
+	votes, err := h.Service.GetThreadVotes(requestContext(c), input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	response, _ := json.Marshal(votes)
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+	return
+}
+
 func (h handler) ThreadGet(c *fasthttp.RequestCtx) {
 	threadInput := SlagOrID(c)
 
-	thread, err := h.Service.GetThread(threadInput)
+	thread, err := h.Service.GetThread(requestContext(c), threadInput)
 	if err != nil {
-		status, respErr, _ := h.ConvertError(err)
-		h.WriteResponse(c, status, respErr)
+		h.writeError(c, err)
 		return
 	}
 
@@ -79,9 +111,7 @@ func (h handler) ThreadGet(c *fasthttp.RequestCtx) {
 
 func (h handler) ThreadUpdate(c *fasthttp.RequestCtx) {
 	threadInput := &models.ThreadUpdate{}
-	err := threadInput.UnmarshalJSON(c.PostBody())
-	if err != nil {
-		log.Println(err)
+	if !h.decode(c, threadInput) || !h.valid(c, threadInput.Validate()) {
 		return
 	}
 
@@ -90,10 +120,9 @@ func (h handler) ThreadUpdate(c *fasthttp.RequestCtx) {
 	threadInput.ThreadID = slagOrID.ThreadID
 	threadInput.Slug = slagOrID.Slug
 
-	thread, err := h.Service.UpdateThread(*threadInput)
+	thread, err := h.Service.UpdateThread(requestContext(c), *threadInput, currentUser(c))
 	if err != nil {
-		status, respErr, _ := h.ConvertError(err)
-		h.WriteResponse(c, status, respErr)
+		h.writeError(c, err)
 		return
 	}
 
@@ -109,16 +138,16 @@ func (h handler) ThreadGetPosts(c *fasthttp.RequestCtx) {
 		Since:    c.QueryArgs().GetUintOrZero("since"),
 		Sort:     string(c.QueryArgs().Peek("sort")),
 		Desc:     getBool("desc", c.QueryArgs()),
+		HideDeleted: getBool("hide_deleted", c.QueryArgs()),
 	}
 
 	slugOrID := SlagOrID(c)
 	threadInput.ThreadID = slugOrID.ThreadID
 	threadInput.Slug = slugOrID.Slug
 
-	posts, err := h.Service.GetThreadPosts(threadInput)
+	posts, err := h.Service.GetThreadPosts(requestContext(c), threadInput)
 	if err != nil {
-		status, respErr, _ := h.ConvertError(err)
-		h.WriteResponse(c, status, respErr)
+		h.writeError(c, err)
 		return
 	}
 
@@ -128,3 +157,23 @@ func (h handler) ThreadGetPosts(c *fasthttp.RequestCtx) {
 	return
 }
 
+func (h handler) ThreadDelete(c *fasthttp.RequestCtx) {
+	threadInput := SlagOrID(c)
+
+	var thread models.Thread
+	var err error
+	if getBool("archive", c.QueryArgs()) {
+		thread, err = h.Service.ArchiveThread(requestContext(c), threadInput, currentUser(c))
+	} else {
+		thread, err = h.Service.DeleteThread(requestContext(c), threadInput, currentUser(c))
+	}
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	response, _ := json.Marshal(thread)
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+	return
+}
@@ -4,19 +4,22 @@ import (
 	"encoding/json"
 	"github.com/pringleskate/tp_db_forum/internal/models"
 	"github.com/valyala/fasthttp"
-	"log"
 )
 
 func (h handler) UserCreate(c *fasthttp.RequestCtx) {
 	userInput := &models.User{}
 	userInput.Nickname = c.UserValue("nickname").(string)
-	err := userInput.UnmarshalJSON(c.PostBody())
-	if err != nil {
-		log.Println(err)
+	if !h.decode(c, userInput) || !h.valid(c, userInput.Validate()) {
 		return
 	}
 
-	user, err := h.Service.CreateUser(*userInput)
+	/* the password travels in the same body but never ends up in models.User, it is optional on creation */
+	password := &models.PasswordChange{}
+	if !h.decode(c, password) || (password.Password != "" && !h.valid(c, password.Validate())) {
+		return
+	}
+
+	user, err := h.Service.CreateUser(requestContext(c), *userInput, password.Password)
 
 
 	if err != nil {
@@ -39,10 +42,9 @@ func (h handler) UserCreate(c *fasthttp.RequestCtx) {
 func (h handler) UserGet(c *fasthttp.RequestCtx) {
 	nickname := c.UserValue("nickname").(string)
 
-	user, err := h.Service.GetUser(nickname)
+	user, err := h.Service.GetUser(requestContext(c), nickname)
 	if err != nil {
-		status, respErr, _ := h.ConvertError(err)
-		h.WriteResponse(c, status, respErr)
+		h.writeError(c, err)
 		return
 	}
 
@@ -55,16 +57,13 @@ func (h handler) UserGet(c *fasthttp.RequestCtx) {
 func (h handler) UserUpdate(c *fasthttp.RequestCtx) {
 	userInput := &models.User{}
 	userInput.Nickname = c.UserValue("nickname").(string)
-	err := userInput.UnmarshalJSON(c.PostBody())
-	if err != nil {
-		log.Println(err)
+	if !h.decode(c, userInput) || !h.valid(c, userInput.ValidateUpdate()) {
 		return
 	}
 
-	user, err := h.Service.UpdateUser(*userInput)
+	user, err := h.Service.UpdateUser(requestContext(c), *userInput, currentUser(c))
 	if err != nil {
-		status, respErr, _ := h.ConvertError(err)
-		h.WriteResponse(c, status, respErr)
+		h.writeError(c, err)
 		return
 	}
 
@@ -74,3 +73,24 @@ func (h handler) UserUpdate(c *fasthttp.RequestCtx) {
 	return
 }
 
+
+/* the user's thread votes ordered by thread id, since is the thread to continue after */
+func (h handler) UserGetVotes(c *fasthttp.RequestCtx) {
+	input := models.UserGetVotes{
+		Nickname: c.UserValue("nickname").(string),
+		Limit:    c.QueryArgs().GetUintOrZero("limit"),
+		Since:    c.QueryArgs().GetUintOrZero("since"),
+		Desc:     getBool("desc", c.QueryArgs()),
+	}
+
+	votes, err := h.Service.GetUserVotes(requestContext(c), input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	response, _ := json.Marshal(votes)
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+	return
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx"
+	"github.com/pringleskate/tp_db_forum/internal/config"
+	"github.com/pringleskate/tp_db_forum/internal/migrations"
+	"strconv"
+)
+
+/* forum [flags] migrate up | down [steps] | status */
+func migrate(cfg config.Config, args []string) error {
+	if cfg.Storage != config.StoragePostgres {
+		return errors.New("migrate: only the postgres storage has a schema")
+	}
+	if len(args) == 0 {
+		return errors.New("migrate: expected up, down or status")
+	}
+
+	poolConfig, err := cfg.PoolConfig()
+	if err != nil {
+		return err
+	}
+	poolConfig.MaxConnections = 2
+
+	db, err := pgx.NewConnPool(poolConfig)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up()
+		for _, m := range applied {
+			fmt.Printf("applied  %04d_%s\n", m.Version, m.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("schema is up to date")
+		}
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("migrate: bad number of steps %q", args[1])
+			}
+		}
+		reverted, err := migrator.Down(steps)
+		for _, m := range reverted {
+			fmt.Printf("reverted %04d_%s\n", m.Version, m.Name)
+		}
+		return err
+	case "status":
+		status, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		for _, s := range status {
+			if s.Applied {
+				fmt.Printf("%04d_%-30s applied at %s\n", s.Version, s.Name, s.AppliedAt.Format("2006-01-02 15:04:05 MST"))
+			} else {
+				fmt.Printf("%04d_%-30s pending\n", s.Version, s.Name)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("migrate: unknown command %q", args[0])
+	}
+}
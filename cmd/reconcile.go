@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx"
+	"github.com/pringleskate/tp_db_forum/internal/config"
+	"github.com/pringleskate/tp_db_forum/internal/models"
+	"github.com/pringleskate/tp_db_forum/internal/storages/databaseService"
+)
+
+/* forum [flags] reconcile [fix] */
+func reconcile(cfg config.Config, args []string) error {
+	if cfg.Storage != config.StoragePostgres {
+		return errors.New("reconcile: the memory storage does not outlive the process")
+	}
+	fix := false
+	if len(args) > 0 {
+		if args[0] != "fix" {
+			return fmt.Errorf("reconcile: unknown command %q", args[0])
+		}
+		fix = true
+	}
+
+	poolConfig, err := cfg.PoolConfig()
+	if err != nil {
+		return err
+	}
+	poolConfig.MaxConnections = 2
+
+	db, err := pgx.NewConnPool(poolConfig)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	result, err := databaseService.NewStorage(db).ReconcileCounters(context.Background(), fix)
+	if err != nil {
+		return err
+	}
+
+	for _, drift := range result.Drifts {
+		fmt.Printf("%-14s %-30s stored %d, actual %d\n", drift.Counter, counterOwner(drift), drift.Stored, drift.Actual)
+	}
+	switch {
+	case len(result.Drifts) == 0:
+		fmt.Println("all counters are consistent")
+	case result.Fixed:
+		fmt.Printf("fixed %d counters\n", len(result.Drifts))
+	default:
+		fmt.Printf("%d counters drifted, run reconcile fix to correct them\n", len(result.Drifts))
+	}
+	return nil
+}
+
+func counterOwner(drift models.CounterDrift) string {
+	switch {
+	case drift.Post != 0:
+		return fmt.Sprintf("forum %s thread %d post %d", drift.Forum, drift.Thread, drift.Post)
+	case drift.Thread != 0:
+		return fmt.Sprintf("forum %s thread %d", drift.Forum, drift.Thread)
+	default:
+		return fmt.Sprintf("forum %s", drift.Forum)
+	}
+}
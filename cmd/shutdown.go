@@ -0,0 +1,74 @@
+package main
+
+import (
+	"github.com/pringleskate/tp_db_forum/internal/logger"
+	"github.com/valyala/fasthttp"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+/*
+Serves until SIGTERM or SIGINT, then stops accepting connections and waits up to timeout
+for the open ones to finish their current request. Requests still running have their context
+cancelled, so their queries stop and they answer 503. A second signal skips the wait.
+Idle keep-alive connections only close at their idle timeout, keep it below the shutdown timeout.
+The storages are closed afterwards either way, so the pool never outlives the server.
+*/
+func serve(server *fasthttp.Server, addr string, timeout time.Duration, st storages, log *logger.Logger) error {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+	defer signal.Stop(signals)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe(addr)
+	}()
+	log.Info("listening", "addr", addr)
+
+	select {
+	case err := <-serveErr:
+		st.close()
+		return err
+	case sig := <-signals:
+		log.Info("shutting down", "signal", sig.String())
+	}
+
+	drained := make(chan error, 1)
+	go func() {
+		drained <- server.Shutdown()
+	}()
+	log.Info("stopped accepting connections", "timeout", timeout.String(), "open", server.GetOpenConnectionsCount())
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	progress := time.NewTicker(time.Second)
+	defer progress.Stop()
+
+	wait := true
+	for wait {
+		select {
+		case err := <-drained:
+			if err != nil {
+				log.Error("shutdown failed", "err", err)
+			} else {
+				log.Info("all connections drained")
+			}
+			wait = false
+		case <-progress.C:
+			log.Info("waiting for connections", "open", server.GetOpenConnectionsCount())
+		case <-deadline.C:
+			log.Warn("shutdown timeout, abandoning connections", "open", server.GetOpenConnectionsCount())
+			wait = false
+		case sig := <-signals:
+			log.Warn("second signal, abandoning connections", "signal", sig.String(), "open", server.GetOpenConnectionsCount())
+			wait = false
+		}
+	}
+
+	log.Info("closing storage")
+	st.close()
+	log.Info("shutdown complete")
+	return nil
+}
@@ -0,0 +1,408 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/jackc/pgx"
+	"github.com/pringleskate/tp_db_forum/internal/logger"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+	Effective config is built in this order, every next source overrides the previous one:
+	defaults -> JSON file (-config or FORUM_CONFIG) -> FORUM_* environment variables -> command-line flags
+*/
+
+const (
+	StoragePostgres = "postgres"
+	StorageMemory   = "memory"
+)
+
+/* test mode opens the endpoints a functional test suite needs, e.g. an unauthenticated service/clear */
+const (
+	ModeProduction = "production"
+	ModeTest       = "test"
+)
+
+type Config struct {
+	Mode     string   `json:"mode"`
+	Storage  string   `json:"storage"`
+	Database Database `json:"database"`
+	Server   Server   `json:"server"`
+	Auth     Auth     `json:"auth"`
+	Admin    Admin    `json:"admin"`
+	Log      Log      `json:"log"`
+}
+
+type Database struct {
+	DSN            string   `json:"dsn"`
+	MaxConnections int      `json:"max_connections"`
+	AcquireTimeout Duration `json:"acquire_timeout"`
+}
+
+type Server struct {
+	Addr         string   `json:"addr"`
+	ReadTimeout  Duration `json:"read_timeout"`
+	WriteTimeout Duration `json:"write_timeout"`
+	IdleTimeout  Duration `json:"idle_timeout"`
+	/* how long SIGTERM/SIGINT waits for in-flight requests before the pool is closed anyway */
+	ShutdownTimeout Duration `json:"shutdown_timeout"`
+	/* deadline for the database work of one request, 0 disables */
+	RequestTimeout Duration `json:"request_timeout"`
+	/* per-route overrides of request_timeout keyed by the route pattern, e.g. "/api/thread/:slug_or_id/posts" */
+	RouteTimeouts Timeouts `json:"route_timeouts"`
+}
+
+type Auth struct {
+	SessionTTL Duration `json:"session_ttl"`
+	Admins     List     `json:"admins"`
+}
+
+type Admin struct {
+	AllowClear bool `json:"allow_clear"`
+}
+
+type Log struct {
+	Level string `json:"level"`
+}
+
+func Default() Config {
+	return Config{
+		Mode:    ModeProduction,
+		Storage: StoragePostgres,
+		Database: Database{
+			DSN:            "postgres://postgres@localhost/forum?sslmode=disable",
+			MaxConnections: 2000,
+		},
+		Server: Server{
+			Addr:            ":5000",
+			ShutdownTimeout: Duration(30 * time.Second),
+			RequestTimeout:  Duration(10 * time.Second),
+		},
+		Auth: Auth{
+			SessionTTL: Duration(24 * time.Hour),
+		},
+		Log: Log{
+			Level: "info",
+		},
+	}
+}
+
+/* rest holds the positional arguments left after the flags, e.g. a subcommand */
+func Load(args []string) (cfg Config, rest []string, err error) {
+	cfg = Default()
+
+	// first pass only looks for -config, flag values themselves are applied last
+	probe := Default()
+	var path string
+	if err = newFlagSet(&probe, &path).Parse(args); err != nil {
+		return cfg, nil, err
+	}
+
+	if path == "" {
+		path = os.Getenv("FORUM_CONFIG")
+	}
+	if path != "" {
+		if err = loadFile(path, &cfg); err != nil {
+			return cfg, nil, err
+		}
+	}
+
+	if err = loadEnv(&cfg); err != nil {
+		return cfg, nil, err
+	}
+
+	fs := newFlagSet(&cfg, &path)
+	fs.SetOutput(ioutil.Discard)
+	if err = fs.Parse(args); err != nil {
+		return cfg, nil, err
+	}
+
+	return cfg, fs.Args(), cfg.Validate()
+}
+
+func newFlagSet(cfg *Config, path *string) *flag.FlagSet {
+	fs := flag.NewFlagSet("forum", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: forum [flags] [migrate up | down [steps] | status | reconcile [fix]]")
+		fs.PrintDefaults()
+	}
+	fs.StringVar(path, "config", *path, "path to JSON config file")
+	fs.StringVar(&cfg.Mode, "mode", cfg.Mode, "production or test")
+	fs.StringVar(&cfg.Storage, "storage", cfg.Storage, "storage backend: postgres or memory")
+	fs.StringVar(&cfg.Database.DSN, "db-dsn", cfg.Database.DSN, "postgres connection string (URI or key=value)")
+	fs.IntVar(&cfg.Database.MaxConnections, "db-max-connections", cfg.Database.MaxConnections, "connection pool size")
+	fs.Var(&cfg.Database.AcquireTimeout, "db-acquire-timeout", "max wait for a free pool connection, 0 waits forever")
+	fs.StringVar(&cfg.Server.Addr, "addr", cfg.Server.Addr, "address to listen on")
+	fs.Var(&cfg.Server.ReadTimeout, "read-timeout", "max duration for reading a request, 0 disables")
+	fs.Var(&cfg.Server.WriteTimeout, "write-timeout", "max duration for writing a response, 0 disables")
+	fs.Var(&cfg.Server.IdleTimeout, "idle-timeout", "max keep-alive idle duration, 0 falls back to read-timeout")
+	fs.Var(&cfg.Server.ShutdownTimeout, "shutdown-timeout", "max wait for in-flight requests on SIGTERM/SIGINT")
+	fs.Var(&cfg.Server.RequestTimeout, "request-timeout", "deadline for the database work of a request, 0 disables")
+	fs.Var(&cfg.Server.RouteTimeouts, "route-timeouts", "per-route request timeouts, e.g. /api/search=2s,/api/thread/:slug_or_id/posts=30s")
+	fs.Var(&cfg.Auth.SessionTTL, "session-ttl", "lifetime of login tokens")
+	fs.Var(&cfg.Auth.Admins, "admins", "comma separated nicknames that are always site admins")
+	fs.StringVar(&cfg.Log.Level, "log-level", cfg.Log.Level, "debug, info, warn or error")
+	fs.BoolVar(&cfg.Admin.AllowClear, "allow-clear", cfg.Admin.AllowClear, "let admins wipe data through /api/service/clear outside test mode")
+	return fs
+}
+
+func loadFile(path string, cfg *Config) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("config: %v", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(cfg); err != nil {
+		return fmt.Errorf("config: %s: %v", path, err)
+	}
+
+	return nil
+}
+
+func loadEnv(cfg *Config) error {
+	if v, ok := os.LookupEnv("FORUM_MODE"); ok {
+		cfg.Mode = v
+	}
+	if v, ok := os.LookupEnv("FORUM_STORAGE"); ok {
+		cfg.Storage = v
+	}
+	if v, ok := os.LookupEnv("FORUM_DB_DSN"); ok {
+		cfg.Database.DSN = v
+	}
+	if v, ok := os.LookupEnv("FORUM_DB_MAX_CONNECTIONS"); ok {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("config: FORUM_DB_MAX_CONNECTIONS: %v", err)
+		}
+		cfg.Database.MaxConnections = n
+	}
+
+	durations := map[string]*Duration{
+		"FORUM_DB_ACQUIRE_TIMEOUT": &cfg.Database.AcquireTimeout,
+		"FORUM_READ_TIMEOUT":       &cfg.Server.ReadTimeout,
+		"FORUM_WRITE_TIMEOUT":      &cfg.Server.WriteTimeout,
+		"FORUM_IDLE_TIMEOUT":       &cfg.Server.IdleTimeout,
+		"FORUM_SHUTDOWN_TIMEOUT":   &cfg.Server.ShutdownTimeout,
+		"FORUM_REQUEST_TIMEOUT":    &cfg.Server.RequestTimeout,
+		"FORUM_SESSION_TTL":        &cfg.Auth.SessionTTL,
+	}
+	for name, d := range durations {
+		if v, ok := os.LookupEnv(name); ok {
+			if err := d.Set(v); err != nil {
+				return fmt.Errorf("config: %s: %v", name, err)
+			}
+		}
+	}
+
+	if v, ok := os.LookupEnv("FORUM_ADDR"); ok {
+		cfg.Server.Addr = v
+	}
+	if v, ok := os.LookupEnv("FORUM_ROUTE_TIMEOUTS"); ok {
+		if err := cfg.Server.RouteTimeouts.Set(v); err != nil {
+			return fmt.Errorf("config: FORUM_ROUTE_TIMEOUTS: %v", err)
+		}
+	}
+	if v, ok := os.LookupEnv("FORUM_ADMINS"); ok {
+		cfg.Auth.Admins.Set(v)
+	}
+	if v, ok := os.LookupEnv("FORUM_LOG_LEVEL"); ok {
+		cfg.Log.Level = v
+	}
+	if v, ok := os.LookupEnv("FORUM_ALLOW_CLEAR"); ok {
+		allow, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("config: FORUM_ALLOW_CLEAR: %v", err)
+		}
+		cfg.Admin.AllowClear = allow
+	}
+
+	return nil
+}
+
+func (c Config) Validate() error {
+	var errs []string
+
+	if c.Mode != ModeProduction && c.Mode != ModeTest {
+		errs = append(errs, fmt.Sprintf("mode: unknown mode %q", c.Mode))
+	}
+
+	switch c.Storage {
+	case StoragePostgres:
+		if _, err := pgx.ParseConnectionString(c.Database.DSN); err != nil {
+			errs = append(errs, fmt.Sprintf("database.dsn: %v", err))
+		}
+		if c.Database.MaxConnections < 2 {
+			errs = append(errs, "database.max_connections: must be at least 2")
+		}
+		if c.Database.AcquireTimeout < 0 {
+			errs = append(errs, "database.acquire_timeout: must not be negative")
+		}
+	case StorageMemory:
+	default:
+		errs = append(errs, fmt.Sprintf("storage: unknown backend %q", c.Storage))
+	}
+	if c.Server.Addr == "" {
+		errs = append(errs, "server.addr: must not be empty")
+	}
+	if c.Server.ReadTimeout < 0 || c.Server.WriteTimeout < 0 || c.Server.IdleTimeout < 0 {
+		errs = append(errs, "server timeouts: must not be negative")
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		errs = append(errs, "server.shutdown_timeout: must be positive")
+	}
+	if c.Server.RequestTimeout < 0 {
+		errs = append(errs, "server.request_timeout: must not be negative")
+	}
+	for route, timeout := range c.Server.RouteTimeouts {
+		if !strings.HasPrefix(route, "/") {
+			errs = append(errs, fmt.Sprintf("server.route_timeouts: %q is not a route pattern", route))
+		}
+		if timeout < 0 {
+			errs = append(errs, fmt.Sprintf("server.route_timeouts: %s must not be negative", route))
+		}
+	}
+	if c.Auth.SessionTTL <= 0 {
+		errs = append(errs, "auth.session_ttl: must be positive")
+	}
+	if _, err := logger.ParseLevel(c.Log.Level); err != nil {
+		errs = append(errs, fmt.Sprintf("log.level: %v", err))
+	}
+
+	if len(errs) != 0 {
+		return errors.New("config: " + strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+/* the deadline of a route, its override if it has one */
+func (s Server) Timeout(route string) time.Duration {
+	if timeout, ok := s.RouteTimeouts[route]; ok {
+		return time.Duration(timeout)
+	}
+	return time.Duration(s.RequestTimeout)
+}
+
+func (c Config) PoolConfig() (pgx.ConnPoolConfig, error) {
+	connConfig, err := pgx.ParseConnectionString(c.Database.DSN)
+	if err != nil {
+		return pgx.ConnPoolConfig{}, err
+	}
+
+	return pgx.ConnPoolConfig{
+		ConnConfig:     connConfig,
+		MaxConnections: c.Database.MaxConnections,
+		AcquireTimeout: time.Duration(c.Database.AcquireTimeout),
+	}, nil
+}
+
+var dsnPassword = regexp.MustCompile(`(password\s*=\s*)('(\\.|[^'])*'|\S+)`)
+
+/* copy of the config safe to print */
+func (c Config) Redacted() Config {
+	if u, err := url.Parse(c.Database.DSN); err == nil && u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), "xxxxx")
+			c.Database.DSN = u.String()
+		}
+	}
+	c.Database.DSN = dsnPassword.ReplaceAllString(c.Database.DSN, "${1}xxxxx")
+	return c
+}
+
+func (c Config) String() string {
+	data, _ := json.Marshal(c.Redacted())
+	return string(data)
+}
+
+type Duration time.Duration
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+func (d *Duration) Set(s string) error {
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"5s\": %v", err)
+	}
+	return d.Set(s)
+}
+
+/* comma separated on the command line and in the environment, a JSON array in the file */
+type List []string
+
+func (l List) String() string {
+	return strings.Join(l, ",")
+}
+
+func (l *List) Set(s string) error {
+	*l = nil
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
+
+/* route=duration pairs, comma separated on the command line and in the environment, a JSON object in the file */
+type Timeouts map[string]Duration
+
+func (t Timeouts) String() string {
+	routes := make([]string, 0, len(t))
+	for route := range t {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+
+	pairs := make([]string, 0, len(routes))
+	for _, route := range routes {
+		pairs = append(pairs, route+"="+t[route].String())
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (t *Timeouts) Set(s string) error {
+	*t = Timeouts{}
+	for _, pair := range strings.Split(s, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		eq := strings.LastIndex(pair, "=")
+		if eq < 0 {
+			return fmt.Errorf("%q is not route=duration", pair)
+		}
+		var d Duration
+		if err := d.Set(pair[eq+1:]); err != nil {
+			return err
+		}
+		(*t)[strings.TrimSpace(pair[:eq])] = d
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+	Leveled logger writing one JSON object per line:
+	{"time":"...","level":"error","msg":"...","request_id":"...", ...fields}
+	Fields are key/value pairs, With returns a child that adds them to every line,
+	e.g. the request middleware hands every handler a logger that already carries the request ID.
+*/
+
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var levelNames = []string{"debug", "info", "warn", "error"}
+
+func (l Level) String() string {
+	if l < LevelDebug || l > LevelError {
+		return fmt.Sprintf("level(%d)", int(l))
+	}
+	return levelNames[l]
+}
+
+func ParseLevel(s string) (Level, error) {
+	for i, name := range levelNames {
+		if strings.EqualFold(s, name) {
+			return Level(i), nil
+		}
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %q", s)
+}
+
+type Logger struct {
+	out    *output
+	level  Level
+	fields []byte
+}
+
+/* shared by a logger and all of its children, so lines from concurrent requests do not interleave */
+type output struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+/* constructor */
+func New(w io.Writer, level Level) *Logger {
+	return &Logger{
+		out:   &output{w: w},
+		level: level,
+	}
+}
+
+func (l *Logger) With(kv ...interface{}) *Logger {
+	child := *l
+	child.fields = appendFields(append([]byte{}, l.fields...), kv)
+	return &child
+}
+
+func (l *Logger) Enabled(level Level) bool {
+	return level >= l.level
+}
+
+func (l *Logger) Debug(msg string, kv ...interface{}) { l.log(LevelDebug, msg, kv) }
+func (l *Logger) Info(msg string, kv ...interface{})  { l.log(LevelInfo, msg, kv) }
+func (l *Logger) Warn(msg string, kv ...interface{})  { l.log(LevelWarn, msg, kv) }
+func (l *Logger) Error(msg string, kv ...interface{}) { l.log(LevelError, msg, kv) }
+
+func (l *Logger) log(level Level, msg string, kv []interface{}) {
+	if !l.Enabled(level) {
+		return
+	}
+
+	line := bytes.NewBufferString(`{"time":`)
+	line.Write(marshal(time.Now().UTC().Format(time.RFC3339Nano)))
+	line.WriteString(`,"level":`)
+	line.Write(marshal(level.String()))
+	line.WriteString(`,"msg":`)
+	line.Write(marshal(msg))
+	line.Write(l.fields)
+	line.Write(appendFields(nil, kv))
+	line.WriteString("}\n")
+
+	l.out.mu.Lock()
+	defer l.out.mu.Unlock()
+	l.out.w.Write(line.Bytes())
+}
+
+/* errors are written as their message, an odd trailing key gets a null value */
+func appendFields(buf []byte, kv []interface{}) []byte {
+	for i := 0; i < len(kv); i += 2 {
+		key := fmt.Sprint(kv[i])
+		var value interface{}
+		if i+1 < len(kv) {
+			value = kv[i+1]
+		}
+		if err, ok := value.(error); ok {
+			value = err.Error()
+		}
+
+		buf = append(buf, ',')
+		buf = append(buf, marshal(key)...)
+		buf = append(buf, ':')
+		buf = append(buf, marshal(value)...)
+	}
+	return buf
+}
+
+func marshal(v interface{}) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprint(v))
+	}
+	return data
+}
+
+var std = New(os.Stderr, LevelInfo)
+
+/* the logger used outside of requests and by FromContext when the context carries none */
+func Default() *Logger {
+	return std
+}
+
+func SetDefault(l *Logger) {
+	std = l
+}
+
+type contextKey struct{}
+
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+func FromContext(ctx context.Context) *Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(contextKey{}).(*Logger); ok {
+			return l
+		}
+	}
+	return std
+}
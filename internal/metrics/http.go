@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx"
+	"github.com/valyala/fasthttp"
+	"strconv"
+	"time"
+)
+
+type HTTP struct {
+	requests *CounterVec
+	duration *HistogramVec
+}
+
+/* constructor */
+func NewHTTP(r *Registry) *HTTP {
+	return &HTTP{
+		requests: r.Counter("forum_http_requests_total", "HTTP requests by route pattern, method and status.", "route", "method", "status"),
+		duration: r.Histogram("forum_http_request_duration_seconds", "HTTP request latency by route pattern, method and status.", DefaultBuckets, "route", "method", "status"),
+	}
+}
+
+/* route is the router pattern, e.g. /api/thread/:slug_or_id/vote, so IDs do not blow up the label set */
+func (m *HTTP) Route(route string, next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(c *fasthttp.RequestCtx) {
+		start := time.Now()
+		next(c)
+
+		method, status := string(c.Method()), strconv.Itoa(c.Response.StatusCode())
+		m.requests.Inc(route, method, status)
+		m.duration.Observe(time.Since(start).Seconds(), route, method, status)
+	}
+}
+
+func Handler(r *Registry) fasthttp.RequestHandler {
+	return func(c *fasthttp.RequestCtx) {
+		c.SetContentType("text/plain; version=0.0.4; charset=utf-8")
+		if err := r.Write(c); err != nil {
+			c.Error(err.Error(), fasthttp.StatusInternalServerError)
+		}
+	}
+}
+
+/* the pgx pool keeps these counters itself, they are only read on scrape */
+func RegisterPool(r *Registry, db *pgx.ConnPool) {
+	r.GaugeFunc("forum_db_pool_max_connections", "Size limit of the connection pool.", func() float64 {
+		return float64(db.Stat().MaxConnections)
+	})
+	r.GaugeFunc("forum_db_pool_open_connections", "Connections the pool has open.", func() float64 {
+		return float64(db.Stat().CurrentConnections)
+	})
+	r.GaugeFunc("forum_db_pool_idle_connections", "Open connections nobody has acquired.", func() float64 {
+		return float64(db.Stat().AvailableConnections)
+	})
+	r.GaugeFunc("forum_db_pool_in_use_connections", "Connections acquired by requests right now.", func() float64 {
+		s := db.Stat()
+		return float64(s.CurrentConnections - s.AvailableConnections)
+	})
+}
+
+type Queries struct {
+	duration *HistogramVec
+}
+
+/* constructor */
+func NewQueries(r *Registry) *Queries {
+	return &Queries{
+		duration: r.Histogram("forum_storage_query_duration_seconds", "Duration of storage method calls, by storage, method and outcome.", DefaultBuckets, "storage", "method", "outcome"),
+	}
+}
+
+/* meant for defer q.Observe("forum", "GetDetails", time.Now(), &err) */
+func (q *Queries) Observe(storage string, method string, start time.Time, err *error) {
+	outcome := "ok"
+	if *err != nil {
+		outcome = "error"
+	}
+	q.duration.Observe(time.Since(start).Seconds(), storage, method, outcome)
+}
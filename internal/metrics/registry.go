@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+	"io"
+)
+
+/*
+	Counter and histogram vectors plus gauges read at scrape time, kept by the Prometheus client.
+	Callers pass label values in the order the labels were declared.
+*/
+
+/* the Prometheus client defaults, in seconds */
+var DefaultBuckets = prometheus.DefBuckets
+
+type Registry struct {
+	registry *prometheus.Registry
+}
+
+/* constructor */
+func NewRegistry() *Registry {
+	return &Registry{
+		registry: prometheus.NewRegistry(),
+	}
+}
+
+/* everything registered, in the text exposition format */
+func (r *Registry) Write(w io.Writer) error {
+	families, err := r.registry.Gather()
+	if err != nil {
+		return err
+	}
+
+	encoder := expfmt.NewEncoder(w, expfmt.FmtText)
+	for _, family := range families {
+		if err = encoder.Encode(family); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type CounterVec struct {
+	vec *prometheus.CounterVec
+}
+
+func (r *Registry) Counter(name string, help string, labels ...string) *CounterVec {
+	vec := prometheus.NewCounterVec(prometheus.CounterOpts{Name: name, Help: help}, labels)
+	r.registry.MustRegister(vec)
+	return &CounterVec{vec: vec}
+}
+
+func (c *CounterVec) Inc(values ...string) {
+	c.vec.WithLabelValues(values...).Inc()
+}
+
+type HistogramVec struct {
+	vec *prometheus.HistogramVec
+}
+
+func (r *Registry) Histogram(name string, help string, buckets []float64, labels ...string) *HistogramVec {
+	vec := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: name, Help: help, Buckets: buckets}, labels)
+	r.registry.MustRegister(vec)
+	return &HistogramVec{vec: vec}
+}
+
+func (h *HistogramVec) Observe(v float64, values ...string) {
+	h.vec.WithLabelValues(values...).Observe(v)
+}
+
+/* read on every scrape, for values somebody else already keeps, e.g. pool stats */
+func (r *Registry) GaugeFunc(name string, help string, fn func() float64) {
+	r.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{Name: name, Help: help}, fn))
+}
@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"bytes"
+	"testing"
+)
+
+/* one metric of every kind, label values that need escaping included */
+func TestRegistryWrite(t *testing.T) {
+	r := NewRegistry()
+	requests := r.Counter("test_requests_total", "Requests.\nBy route.", "route")
+	duration := r.Histogram("test_duration_seconds", "Duration.", []float64{.1, 1}, "route")
+	r.GaugeFunc("test_connections", "Open connections.", func() float64 { return 3 })
+
+	requests.Inc(`/a`)
+	requests.Inc(`/a`)
+	requests.Inc("/\"b\"\\")
+	duration.Observe(.05, "/a")
+	duration.Observe(.5, "/a")
+	duration.Observe(5, "/a")
+
+	want := `# HELP test_connections Open connections.
+# TYPE test_connections gauge
+test_connections 3
+# HELP test_duration_seconds Duration.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{route="/a",le="0.1"} 1
+test_duration_seconds_bucket{route="/a",le="1"} 2
+test_duration_seconds_bucket{route="/a",le="+Inf"} 3
+test_duration_seconds_sum{route="/a"} 5.55
+test_duration_seconds_count{route="/a"} 3
+# HELP test_requests_total Requests.\nBy route.
+# TYPE test_requests_total counter
+test_requests_total{route="/\"b\"\\"} 1
+test_requests_total{route="/a"} 2
+`
+
+	var got bytes.Buffer
+	if err := r.Write(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", got.String(), want)
+	}
+}
@@ -0,0 +1,261 @@
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"github.com/jackc/pgx"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+//go:embed sql/*.sql
+var files embed.FS
+
+/* any constant works as long as every migrator uses the same one */
+const lockKey = 5000_0001
+
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+type Migrator interface {
+	Up() (applied []Migration, err error)
+	Down(steps int) (reverted []Migration, err error)
+	Status() (status []Status, err error)
+}
+
+type migrator struct {
+	db         *pgx.ConnPool
+	migrations []Migration
+}
+
+/* constructor */
+func NewMigrator(db *pgx.ConnPool) (Migrator, error) {
+	migrations, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	return &migrator{
+		db:         db,
+		migrations: migrations,
+	}, nil
+}
+
+/* reads the embedded sql/NNNN_name.{up,down}.sql files ordered by version */
+func Load() (migrations []Migration, err error) {
+	entries, err := files.ReadDir("sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("migrations: unexpected file %s", entry.Name())
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migrations: version %d has two names: %s and %s", version, m.Name, match[2])
+		}
+
+		body, err := files.ReadFile(path.Join("sql", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if match[3] == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migrations: version %d needs both up and down files", m.Version)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+func (m *migrator) Up() (applied []Migration, err error) {
+	err = m.locked(func(conn *pgx.Conn, done map[int]time.Time) error {
+		for _, migration := range m.migrations {
+			if _, ok := done[migration.Version]; ok {
+				continue
+			}
+
+			err := inTx(conn, migration.Up,
+				"INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", migration.Version, migration.Name)
+			if err != nil {
+				return fmt.Errorf("migrations: %04d_%s up: %v", migration.Version, migration.Name, err)
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+
+	return
+}
+
+func (m *migrator) Down(steps int) (reverted []Migration, err error) {
+	err = m.locked(func(conn *pgx.Conn, done map[int]time.Time) error {
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := done[migration.Version]; !ok {
+				continue
+			}
+
+			err := inTx(conn, migration.Down,
+				"DELETE FROM schema_migrations WHERE version = $1", migration.Version)
+			if err != nil {
+				return fmt.Errorf("migrations: %04d_%s down: %v", migration.Version, migration.Name, err)
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+
+	return
+}
+
+func (m *migrator) Status() (status []Status, err error) {
+	conn, err := m.db.Acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer m.db.Release(conn)
+
+	done, err := appliedVersions(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, migration := range m.migrations {
+		appliedAt, ok := done[migration.Version]
+		status = append(status, Status{Migration: migration, Applied: ok, AppliedAt: appliedAt})
+	}
+
+	return status, nil
+}
+
+/* embedded migrations not recorded in schema_migrations yet, read only so it is cheap enough for readiness checks */
+func Pending(conn *pgx.Conn) (pending []Migration, err error) {
+	migrations, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	done, err := appliedVersions(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, migration := range migrations {
+		if _, ok := done[migration.Version]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+
+	return pending, nil
+}
+
+/* runs fn on a single connection holding the advisory lock, so concurrent migrators wait for each other */
+func (m *migrator) locked(fn func(conn *pgx.Conn, done map[int]time.Time) error) error {
+	conn, err := m.db.Acquire()
+	if err != nil {
+		return err
+	}
+	defer m.db.Release(conn)
+
+	if _, err = conn.Exec("SELECT pg_advisory_lock($1)", lockKey); err != nil {
+		return err
+	}
+	defer conn.Exec("SELECT pg_advisory_unlock($1)", lockKey)
+
+	_, err = conn.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		name       TEXT NOT NULL,
+		applied_at TIMESTAMP WITH TIME ZONE DEFAULT now() NOT NULL
+	)`)
+	if err != nil {
+		return err
+	}
+
+	done, err := appliedVersions(conn)
+	if err != nil {
+		return err
+	}
+
+	return fn(conn, done)
+}
+
+func appliedVersions(conn *pgx.Conn) (map[int]time.Time, error) {
+	done := make(map[int]time.Time)
+
+	var exists bool
+	err := conn.QueryRow("SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists)
+	if err != nil || !exists {
+		return done, err
+	}
+
+	rows, err := conn.Query("SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err = rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		done[version] = appliedAt
+	}
+
+	return done, rows.Err()
+}
+
+func inTx(conn *pgx.Conn, script string, bookkeeping string, args ...interface{}) error {
+	tx, err := conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.Exec(script); err != nil {
+		return err
+	}
+	if _, err = tx.Exec(bookkeeping, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS votes, forum_users, posts, threads, forums, users CASCADE;
+DROP SEQUENCE IF EXISTS post_id_seq;
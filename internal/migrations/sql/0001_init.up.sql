@@ -0,0 +1,85 @@
+-- initial schema, written with IF NOT EXISTS so databases created by the old init.sql are adopted as is
+CREATE EXTENSION IF NOT EXISTS citext;
+
+CREATE TABLE IF NOT EXISTS users
+(
+    ID       SERIAL NOT NULL PRIMARY KEY,
+    nickname CITEXT NOT NULL UNIQUE COLLATE "POSIX",
+    fullname TEXT   NOT NULL,
+    email    CITEXT NOT NULL UNIQUE,
+    about    TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_nick_nick ON users (nickname);
+CREATE INDEX IF NOT EXISTS idx_nick_email ON users (email);
+CREATE INDEX IF NOT EXISTS idx_nick_cover ON users (nickname, fullname, about, email);
+
+CREATE TABLE IF NOT EXISTS forums
+(
+    ID        SERIAL                             NOT NULL PRIMARY KEY,
+    slug      CITEXT                             NOT NULL UNIQUE,
+    threads   INTEGER DEFAULT 0                  NOT NULL,
+    posts     INTEGER DEFAULT 0                  NOT NULL,
+    title     TEXT                               NOT NULL,
+    user_nick CITEXT REFERENCES users (nickname) NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_forum_slug ON forums USING hash (slug);
+
+CREATE TABLE IF NOT EXISTS threads
+(
+    ID      SERIAL                                 NOT NULL PRIMARY KEY,
+    author  CITEXT                                 NOT NULL REFERENCES users (nickname),
+    created TIMESTAMP WITH TIME ZONE DEFAULT now() NOT NULL,
+    forum   CITEXT REFERENCES forums (slug)        NOT NULL,
+    message TEXT                                   NOT NULL,
+    slug    CITEXT UNIQUE,
+    title   TEXT                                   NOT NULL,
+    votes   INTEGER DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_thread_id ON threads (id);
+CREATE INDEX IF NOT EXISTS idx_thread_slug ON threads (slug);
+CREATE INDEX IF NOT EXISTS idx_thread_coverage ON threads (forum, created, id, slug, author, title, message, votes);
+
+CREATE TABLE IF NOT EXISTS forum_users
+(
+    forumID INTEGER REFERENCES forums (ID),
+    userID  INTEGER REFERENCES users (ID),
+    CONSTRAINT uniq UNIQUE (forumID, userID)
+);
+CREATE INDEX IF NOT EXISTS idx_forum_user ON forum_users (forumID, userID);
+
+CREATE TABLE IF NOT EXISTS votes
+(
+    user_nick CITEXT REFERENCES users (nickname) NOT NULL,
+    voice     BOOLEAN                            NOT NULL,
+    thread    INTEGER REFERENCES threads (ID)    NOT NULL,
+    CONSTRAINT uniq_votes UNIQUE (user_nick, thread)
+);
+CREATE INDEX IF NOT EXISTS idx_vote ON votes (thread, voice);
+
+CREATE SEQUENCE IF NOT EXISTS post_id_seq
+    AS integer
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+CREATE TABLE IF NOT EXISTS posts
+(
+    id      INTEGER DEFAULT nextval('post_id_seq'::regclass) NOT NULL PRIMARY KEY,
+    author  CITEXT                                           NOT NULL REFERENCES users (nickname),
+    created TEXT                                             NOT NULL,
+    forum   CITEXT REFERENCES forums (slug)                  NOT NULL,
+    edited  BOOLEAN DEFAULT false                            NOT NULL,
+    message TEXT                                             NOT NULL,
+    parent  INTEGER DEFAULT 0                                NOT NULL,
+    thread  INTEGER REFERENCES threads (ID)                  NOT NULL,
+    path    INTEGER[] DEFAULT '{0}'::INTEGER[]               NOT NULL
+);
+ALTER SEQUENCE post_id_seq OWNED BY posts.id;
+
+CREATE INDEX IF NOT EXISTS post_author_forum_index ON posts USING btree (author, forum);
+CREATE INDEX IF NOT EXISTS post_forum_index ON posts USING btree (forum);
+CREATE INDEX IF NOT EXISTS post_parent_index ON posts USING btree (parent);
+CREATE INDEX IF NOT EXISTS post_path_index ON posts USING gin (path);
+CREATE INDEX IF NOT EXISTS post_thread_index ON posts USING btree (thread);
@@ -0,0 +1,2 @@
+ALTER TABLE posts
+    ALTER COLUMN created TYPE TEXT USING to_char(created AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS.US"Z"');
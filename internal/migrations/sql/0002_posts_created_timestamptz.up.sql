@@ -0,0 +1,2 @@
+ALTER TABLE posts
+    ALTER COLUMN created TYPE TIMESTAMP WITH TIME ZONE USING created::TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,2 @@
+ALTER TABLE threads
+    DROP COLUMN archived;
@@ -0,0 +1,2 @@
+ALTER TABLE threads
+    ADD COLUMN archived BOOLEAN DEFAULT false NOT NULL;
@@ -0,0 +1,6 @@
+DROP INDEX idx_forum_activity;
+DROP INDEX idx_forum_posts;
+DROP INDEX idx_forum_threads;
+
+ALTER TABLE forums
+    DROP COLUMN activity;
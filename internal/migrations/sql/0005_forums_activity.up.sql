@@ -0,0 +1,15 @@
+ALTER TABLE forums
+    ADD COLUMN activity TIMESTAMP WITH TIME ZONE DEFAULT now() NOT NULL;
+
+UPDATE forums f
+SET activity = a.activity
+FROM (SELECT forum, max(created) AS activity
+      FROM (SELECT forum, created FROM threads
+            UNION ALL
+            SELECT forum, created FROM posts) c
+      GROUP BY forum) a
+WHERE a.forum = f.slug;
+
+CREATE INDEX idx_forum_threads ON forums (threads, slug);
+CREATE INDEX idx_forum_posts ON forums (posts, slug);
+CREATE INDEX idx_forum_activity ON forums (activity, slug);
@@ -0,0 +1,8 @@
+DROP TRIGGER threads_search ON threads;
+DROP FUNCTION threads_search();
+ALTER TABLE threads
+    DROP COLUMN search;
+
+DROP TRIGGER posts_search ON posts;
+ALTER TABLE posts
+    DROP COLUMN search;
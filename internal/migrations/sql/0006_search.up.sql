@@ -0,0 +1,35 @@
+-- 'simple' keeps the index language agnostic, posts are written in more than one language
+ALTER TABLE posts
+    ADD COLUMN search TSVECTOR;
+UPDATE posts
+SET search = to_tsvector('simple', message);
+CREATE INDEX idx_posts_search ON posts USING gin (search);
+
+CREATE TRIGGER posts_search
+    BEFORE INSERT OR UPDATE OF message
+    ON posts
+    FOR EACH ROW
+EXECUTE PROCEDURE tsvector_update_trigger(search, 'pg_catalog.simple', message);
+
+-- titles outrank bodies, so threads get a weighted vector built by hand
+CREATE FUNCTION threads_search() RETURNS trigger AS
+$$
+BEGIN
+    NEW.search := setweight(to_tsvector('simple', NEW.title), 'A') ||
+                  setweight(to_tsvector('simple', NEW.message), 'B');
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE threads
+    ADD COLUMN search TSVECTOR;
+UPDATE threads
+SET search = setweight(to_tsvector('simple', title), 'A') ||
+             setweight(to_tsvector('simple', message), 'B');
+CREATE INDEX idx_threads_search ON threads USING gin (search);
+
+CREATE TRIGGER threads_search
+    BEFORE INSERT OR UPDATE OF title, message
+    ON threads
+    FOR EACH ROW
+EXECUTE PROCEDURE threads_search();
@@ -0,0 +1,4 @@
+DROP TABLE sessions;
+
+ALTER TABLE users
+    DROP COLUMN password_hash;
@@ -0,0 +1,13 @@
+-- users created before this migration have no password and cannot log in until one is set
+ALTER TABLE users
+    ADD COLUMN password_hash BYTEA;
+
+-- only a sha256 of the token is kept, a leaked table does not leak live sessions
+CREATE TABLE sessions
+(
+    token_hash BYTEA PRIMARY KEY,
+    userID     INTEGER REFERENCES users (ID) ON DELETE CASCADE NOT NULL,
+    created    TIMESTAMP WITH TIME ZONE DEFAULT now()           NOT NULL,
+    expires    TIMESTAMP WITH TIME ZONE                         NOT NULL
+);
+CREATE INDEX idx_sessions_expires ON sessions (expires);
@@ -0,0 +1,2 @@
+DROP TABLE forum_moderators;
+DROP TABLE user_roles;
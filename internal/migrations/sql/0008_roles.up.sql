@@ -0,0 +1,15 @@
+-- site wide roles, users without a row are plain members
+CREATE TABLE user_roles
+(
+    userID INTEGER PRIMARY KEY REFERENCES users (ID) ON DELETE CASCADE,
+    role   TEXT NOT NULL CHECK (role IN ('admin', 'banned'))
+);
+
+CREATE TABLE forum_moderators
+(
+    forumID    INTEGER REFERENCES forums (ID) ON DELETE CASCADE NOT NULL,
+    userID     INTEGER REFERENCES users (ID) ON DELETE CASCADE  NOT NULL,
+    granted_by CITEXT                                           NOT NULL,
+    granted_at TIMESTAMP WITH TIME ZONE DEFAULT now()           NOT NULL,
+    PRIMARY KEY (forumID, userID)
+);
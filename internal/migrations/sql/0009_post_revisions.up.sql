@@ -0,0 +1,10 @@
+-- every version of an edited post, revision 1 is the text as it was first posted
+CREATE TABLE post_revisions
+(
+    postID   INTEGER REFERENCES posts (ID) ON DELETE CASCADE NOT NULL,
+    revision INTEGER                                        NOT NULL,
+    message  TEXT                                           NOT NULL,
+    editor   CITEXT                                         NOT NULL,
+    created  TIMESTAMP WITH TIME ZONE DEFAULT now()         NOT NULL,
+    PRIMARY KEY (postID, revision)
+);
@@ -0,0 +1,3 @@
+DROP TABLE post_votes;
+ALTER TABLE posts
+    DROP COLUMN votes;
@@ -0,0 +1,12 @@
+ALTER TABLE posts
+    ADD COLUMN votes INTEGER DEFAULT 0 NOT NULL;
+
+-- same shape as votes, which only covers threads
+CREATE TABLE post_votes
+(
+    user_nick CITEXT REFERENCES users (nickname)              NOT NULL,
+    voice     BOOLEAN                                         NOT NULL,
+    post      INTEGER REFERENCES posts (ID) ON DELETE CASCADE NOT NULL,
+    CONSTRAINT uniq_post_votes UNIQUE (user_nick, post)
+);
+CREATE INDEX idx_post_votes_post ON post_votes (post);
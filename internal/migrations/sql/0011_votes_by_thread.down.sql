@@ -0,0 +1 @@
+DROP INDEX idx_votes_thread_user;
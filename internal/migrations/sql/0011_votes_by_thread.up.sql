@@ -0,0 +1,2 @@
+-- vote listings of a thread walk it ordered by nickname
+CREATE INDEX idx_votes_thread_user ON votes (thread, user_nick);
@@ -0,0 +1,84 @@
+package models
+
+/*
+	Every error that leaves a storage or the service is an Error of one of the kinds below.
+	Start from the sentinel of the kind and refine it, e.g.
+		models.ErrNotFound.WithMessage("cannot find user")
+		models.ErrInternal.Wrap(err)
+	and test for the kind with errors.Is(err, models.ErrNotFound).
+	Code is the stable machine-readable name clients may switch on, Message is for humans,
+	Cause is only logged and never sent to the client.
+*/
+
+type Kind int
+
+const (
+	/* the zero value, so an Error nobody classified still ends up as a 500 */
+	KindInternal Kind = iota
+	KindValidation
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindConflict
+	/* the request ran out of its deadline */
+	KindTimeout
+	/* the server cannot take the request right now, e.g. no free database connection */
+	KindUnavailable
+)
+
+var kindNames = []string{"internal", "validation", "unauthorized", "forbidden", "not_found", "conflict", "timeout", "unavailable"}
+
+func (k Kind) String() string {
+	if k < KindInternal || k > KindUnavailable {
+		return kindNames[KindInternal]
+	}
+	return kindNames[k]
+}
+
+var (
+	ErrInternal     = Error{Kind: KindInternal, Code: "internal", Message: "internal error"}
+	ErrValidation   = Error{Kind: KindValidation, Code: "invalid_request", Message: "invalid request"}
+	ErrUnauthorized = Error{Kind: KindUnauthorized, Code: "unauthorized", Message: "unauthorized"}
+	ErrForbidden    = Error{Kind: KindForbidden, Code: "forbidden", Message: "forbidden"}
+	ErrNotFound     = Error{Kind: KindNotFound, Code: "not_found", Message: "not found"}
+	ErrConflict     = Error{Kind: KindConflict, Code: "conflict", Message: "conflict"}
+	ErrTimeout      = Error{Kind: KindTimeout, Code: "timeout", Message: "request timed out"}
+	ErrUnavailable  = Error{Kind: KindUnavailable, Code: "unavailable", Message: "service unavailable"}
+)
+
+func (e Error) Error() string {
+	if e.Cause != nil {
+		return e.Message + ": " + e.Cause.Error()
+	}
+	return e.Message
+}
+
+/* errors.Is matches on the kind, whatever the message, code or cause */
+func (e Error) Is(target error) bool {
+	t, ok := target.(Error)
+	return ok && t.Kind == e.Kind
+}
+
+func (e Error) Unwrap() error {
+	return e.Cause
+}
+
+func (e Error) WithMessage(message string) Error {
+	e.Message = message
+	return e
+}
+
+func (e Error) WithCode(code string) Error {
+	e.Code = code
+	return e
+}
+
+func (e Error) WithFields(fields []FieldError) Error {
+	e.Fields = fields
+	return e
+}
+
+func (e Error) Wrap(cause error) Error {
+	e.Cause = cause
+	return e
+}
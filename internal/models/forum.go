@@ -11,15 +11,22 @@ type RespError struct {
 
 //easyjson:json
 type Error struct {
-	Code string `json:"-"`
+	Kind Kind `json:"-"`
+	Code string `json:"code"`
 	Message string `json:"message"`
+	Fields []FieldError `json:"fields,omitempty"`
+	Cause error `json:"-"`
 	//Message RespError
 }
 
-func (e Error) Error() string {
-	return e.Code
+/* one rejected input field, Code is one of the Invalid* constants */
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
 }
 
+
 //easyjson:json
 type Forum struct {
 	Slug string `json:"slug,omitempty"`
@@ -40,6 +47,14 @@ type ForumInput struct {
 	Slug string
 }
 
+type ForumList struct {
+	Limit int
+	Since string
+	Sort string
+	Desc bool
+	Title string
+}
+
 type ForumGetUsers struct {
 	Slug string
 	Limit int
@@ -52,6 +67,7 @@ type ForumGetThreads struct {
 	Limit int
 	Since string
 	Desc bool
+	Archived bool
 }
 
 type UserInput struct {
@@ -66,6 +82,37 @@ type User struct {
 	About string `json:"about,omitempty"`
 }
 
+//easyjson:json
+type Credentials struct {
+	Nickname string `json:"nickname"`
+	Password string `json:"password"`
+}
+
+//easyjson:json
+type Session struct {
+	Nickname string    `json:"nickname"`
+	Token    string    `json:"token"`
+	Expires  time.Time `json:"expires"`
+}
+
+//easyjson:json
+type PasswordChange struct {
+	Password string `json:"password"`
+}
+
+/* site wide roles, moderators are granted per forum */
+const (
+	RoleAdmin     = "admin"
+	RoleModerator = "moderator"
+	RoleMember    = "member"
+	RoleBanned    = "banned"
+)
+
+//easyjson:json
+type RoleChange struct {
+	Role string `json:"role"`
+}
+
 //easyjson:json
 type Thread struct {
 	Author  string    `json:"author,omitempty"`
@@ -76,6 +123,7 @@ type Thread struct {
 	Slug    string    `json:"slug,omitempty"`
 	Title   string    `json:"title,omitempty"`
 	Votes   int       `json:"votes,omitempty"`
+	IsArchived bool   `json:"isArchived,omitempty"`
 }
 
 //easyjson:json
@@ -98,6 +146,7 @@ type ThreadGetPosts struct {
 	Since int
 	Sort string
 	Desc bool
+	HideDeleted bool
 }
 
 type PostInput struct {
@@ -124,10 +173,11 @@ type Post struct {
 	Author   string `json:"author,omitempty"`   // Автор, написавший данное сообщение.
 	Message  string `json:"message,omitempty"`  // Собственно сообщение форума.
 	IsEdited bool   `json:"isEdited,omitempty"` // Истина, если данное сообщение было изменено.
+	IsDeleted bool  `json:"isDeleted,omitempty"` // Истина, если сообщение удалено (остаётся в дереве без текста).
+	Votes    int    `json:"votes,omitempty"`    // Сумма голосов за сообщение.
 	Forum    string `json:"forum,omitempty"`    // Идентификатор форума (slug) данного сообещния.
 	//	Thread   int32  `json:"thread"`   // Идентификатор ветви (id) обсуждения данного сообещния.
-	//Created  time.Time `json:"created,omitempty"`  // Дата создания сообщения на форуме.
-	Created  string `json:"created,omitempty"`
+	Created  time.Time `json:"created,omitempty"`  // Дата создания сообщения на форуме.
 }
 /*
 type Post struct {
@@ -145,6 +195,35 @@ type Post struct {
 
 //type Posts []*Post
 
+/* one version of a post, Editor is the author for revision 1 */
+//easyjson:json
+type PostRevision struct {
+	Revision int       `json:"revision"`
+	Message  string    `json:"message"`
+	Editor   string    `json:"editor"`
+	Created  time.Time `json:"created"`
+}
+
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+)
+
+/* line by line difference between two revisions, Lines read from top to bottom */
+//easyjson:json
+type PostDiff struct {
+	Post  int          `json:"post"`
+	From  PostRevision `json:"from"`
+	To    PostRevision `json:"to"`
+	Lines []DiffLine   `json:"lines"`
+}
+
+type DiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
 //easyjson:json
 type PostFull struct {
 	Author *User `json:"author,omitempty"`
@@ -153,11 +232,53 @@ type PostFull struct {
 	Thread *Thread `json:"thread,omitempty"`
 }
 
+type SearchInput struct {
+	Query string
+	Forum string
+	Author string
+	Since time.Time
+	Limit int
+	Related string
+}
+
+//easyjson:json
+type SearchResult struct {
+	Kind    string  `json:"kind"`    // "post" или "thread".
+	Rank    float32 `json:"rank"`    // Релевантность, больше - выше в выдаче.
+	Snippet string  `json:"snippet"` // Фрагмент текста с совпадениями в <b></b>.
+	PostFull
+}
+
 //easyjson:json
 type Vote struct {
 	User string `json:"nickname"`
 	Voice int `json:"voice"`
 	Thread ThreadInput `json:"_"`
+	/* set instead of Thread when the vote is for a post */
+	Post int `json:"-"`
+}
+
+/* since is the nickname to continue after */
+type ThreadGetVotes struct {
+	ThreadInput
+	Limit int
+	Since string
+	Desc bool
+}
+
+/* since is the thread id to continue after */
+type UserGetVotes struct {
+	Nickname string
+	Limit int
+	Since int
+	Desc bool
+}
+
+//easyjson:json
+type VoteRecord struct {
+	User   string `json:"nickname"`
+	Thread int    `json:"thread"`
+	Voice  int    `json:"voice"`
 }
 
 //easyjson:json
@@ -166,4 +287,59 @@ type Status struct {
 	Post   int64 `json:"post"`
 	Thread int32 `json:"thread"`
 	User   int32 `json:"user"`
-}
\ No newline at end of file
+}
+/* Forum scopes the wipe to one forum, Confirm is the token a previous unconfirmed call handed out */
+type ClearInput struct {
+	Forum   string
+	Confirm string
+}
+
+//easyjson:json
+type ClearConfirmation struct {
+	Confirm string    `json:"confirm"`
+	Forum   string    `json:"forum,omitempty"`
+	Expires time.Time `json:"expires"`
+}
+
+/* denormalized counters the reconciliation recomputes, named table.column */
+const (
+	CounterForumThreads = "forum.threads"
+	CounterForumPosts   = "forum.posts"
+	CounterThreadVotes  = "thread.votes"
+	CounterPostVotes    = "post.votes"
+)
+
+/* one counter whose stored value differs from what the rows it summarizes add up to */
+type CounterDrift struct {
+	Counter string `json:"counter"`
+	Forum   string `json:"forum"`
+	Thread  int    `json:"thread,omitempty"`
+	Post    int    `json:"post,omitempty"`
+	Stored  int    `json:"stored"`
+	Actual  int    `json:"actual"`
+}
+
+//easyjson:json
+type Reconciliation struct {
+	Fixed  bool           `json:"fixed"`
+	Drifts []CounterDrift `json:"drifts"`
+}
+
+const (
+	HealthOK   = "ok"
+	HealthFail = "fail"
+	HealthSkip = "skipped"
+)
+
+//easyjson:json
+type Health struct {
+	Status string        `json:"status"`
+	Checks []HealthCheck `json:"checks,omitempty"`
+}
+
+type HealthCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
@@ -17,7 +17,87 @@ var (
 	_ easyjson.Marshaler
 )
 
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels(in *jlexer.Lexer, out *Vote) {
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels(in *jlexer.Lexer, out *VoteRecord) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "nickname":
+			out.User = string(in.String())
+		case "thread":
+			out.Thread = int(in.Int())
+		case "voice":
+			out.Voice = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels(out *jwriter.Writer, in VoteRecord) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"nickname\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.User))
+	}
+	{
+		const prefix string = ",\"thread\":"
+		out.RawString(prefix)
+		out.Int(int(in.Thread))
+	}
+	{
+		const prefix string = ",\"voice\":"
+		out.RawString(prefix)
+		out.Int(int(in.Voice))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v VoteRecord) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v VoteRecord) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *VoteRecord) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *VoteRecord) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels(l, v)
+}
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels1(in *jlexer.Lexer, out *Vote) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -52,7 +132,7 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels(in *j
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels(out *jwriter.Writer, in Vote) {
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels1(out *jwriter.Writer, in Vote) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -77,27 +157,27 @@ func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels(out *
 // MarshalJSON supports json.Marshaler interface
 func (v Vote) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels(&w, v)
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels1(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v Vote) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels(w, v)
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels1(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *Vote) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels(&r, v)
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels1(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *Vote) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels(l, v)
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels1(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels1(in *jlexer.Lexer, out *UserInput) {
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels2(in *jlexer.Lexer, out *UserInput) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -128,7 +208,7 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels1(in *
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels1(out *jwriter.Writer, in UserInput) {
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels2(out *jwriter.Writer, in UserInput) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -143,27 +223,114 @@ func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels1(out
 // MarshalJSON supports json.Marshaler interface
 func (v UserInput) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels1(&w, v)
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels2(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v UserInput) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels1(w, v)
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels2(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *UserInput) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels1(&r, v)
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels2(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *UserInput) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels1(l, v)
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels2(l, v)
+}
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels3(in *jlexer.Lexer, out *UserGetVotes) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "Nickname":
+			out.Nickname = string(in.String())
+		case "Limit":
+			out.Limit = int(in.Int())
+		case "Since":
+			out.Since = int(in.Int())
+		case "Desc":
+			out.Desc = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels3(out *jwriter.Writer, in UserGetVotes) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"Nickname\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Nickname))
+	}
+	{
+		const prefix string = ",\"Limit\":"
+		out.RawString(prefix)
+		out.Int(int(in.Limit))
+	}
+	{
+		const prefix string = ",\"Since\":"
+		out.RawString(prefix)
+		out.Int(int(in.Since))
+	}
+	{
+		const prefix string = ",\"Desc\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Desc))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v UserGetVotes) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels3(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v UserGetVotes) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels3(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *UserGetVotes) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels3(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *UserGetVotes) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels3(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels2(in *jlexer.Lexer, out *User) {
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels4(in *jlexer.Lexer, out *User) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -200,7 +367,7 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels2(in *
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels2(out *jwriter.Writer, in User) {
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels4(out *jwriter.Writer, in User) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -246,27 +413,27 @@ func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels2(out
 // MarshalJSON supports json.Marshaler interface
 func (v User) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels2(&w, v)
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels4(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v User) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels2(w, v)
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels4(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *User) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels2(&r, v)
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels4(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *User) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels2(l, v)
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels4(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels3(in *jlexer.Lexer, out *ThreadUpdate) {
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels5(in *jlexer.Lexer, out *ThreadUpdate) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -301,7 +468,7 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels3(in *
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels3(out *jwriter.Writer, in ThreadUpdate) {
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels5(out *jwriter.Writer, in ThreadUpdate) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -326,27 +493,27 @@ func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels3(out
 // MarshalJSON supports json.Marshaler interface
 func (v ThreadUpdate) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels3(&w, v)
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels5(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v ThreadUpdate) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels3(w, v)
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels5(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *ThreadUpdate) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels3(&r, v)
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels5(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *ThreadUpdate) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels3(l, v)
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels5(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels4(in *jlexer.Lexer, out *ThreadInput) {
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels6(in *jlexer.Lexer, out *ThreadInput) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -377,7 +544,7 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels4(in *
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels4(out *jwriter.Writer, in ThreadInput) {
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels6(out *jwriter.Writer, in ThreadInput) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -392,27 +559,114 @@ func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels4(out
 // MarshalJSON supports json.Marshaler interface
 func (v ThreadInput) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels4(&w, v)
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels6(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v ThreadInput) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels4(w, v)
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels6(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *ThreadInput) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels4(&r, v)
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels6(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *ThreadInput) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels4(l, v)
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels6(l, v)
+}
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels7(in *jlexer.Lexer, out *ThreadGetVotes) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "Limit":
+			out.Limit = int(in.Int())
+		case "Since":
+			out.Since = string(in.String())
+		case "Desc":
+			out.Desc = bool(in.Bool())
+		case "thread":
+			out.ThreadID = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels7(out *jwriter.Writer, in ThreadGetVotes) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"Limit\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Limit))
+	}
+	{
+		const prefix string = ",\"Since\":"
+		out.RawString(prefix)
+		out.String(string(in.Since))
+	}
+	{
+		const prefix string = ",\"Desc\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Desc))
+	}
+	{
+		const prefix string = ",\"thread\":"
+		out.RawString(prefix)
+		out.Int(int(in.ThreadID))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ThreadGetVotes) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels7(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ThreadGetVotes) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels7(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ThreadGetVotes) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels7(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ThreadGetVotes) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels7(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels5(in *jlexer.Lexer, out *ThreadGetPosts) {
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels8(in *jlexer.Lexer, out *ThreadGetPosts) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -439,6 +693,8 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels5(in *
 			out.Sort = string(in.String())
 		case "Desc":
 			out.Desc = bool(in.Bool())
+		case "HideDeleted":
+			out.HideDeleted = bool(in.Bool())
 		case "thread":
 			out.ThreadID = int(in.Int())
 		default:
@@ -451,7 +707,7 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels5(in *
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels5(out *jwriter.Writer, in ThreadGetPosts) {
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels8(out *jwriter.Writer, in ThreadGetPosts) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -475,6 +731,11 @@ func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels5(out
 		out.RawString(prefix)
 		out.Bool(bool(in.Desc))
 	}
+	{
+		const prefix string = ",\"HideDeleted\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.HideDeleted))
+	}
 	{
 		const prefix string = ",\"thread\":"
 		out.RawString(prefix)
@@ -486,27 +747,27 @@ func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels5(out
 // MarshalJSON supports json.Marshaler interface
 func (v ThreadGetPosts) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels5(&w, v)
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels8(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v ThreadGetPosts) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels5(w, v)
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels8(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *ThreadGetPosts) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels5(&r, v)
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels8(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *ThreadGetPosts) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels5(l, v)
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels8(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels6(in *jlexer.Lexer, out *Thread) {
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels9(in *jlexer.Lexer, out *Thread) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -543,6 +804,8 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels6(in *
 			out.Title = string(in.String())
 		case "votes":
 			out.Votes = int(in.Int())
+		case "isArchived":
+			out.IsArchived = bool(in.Bool())
 		default:
 			in.SkipRecursive()
 		}
@@ -553,7 +816,7 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels6(in *
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels6(out *jwriter.Writer, in Thread) {
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels9(out *jwriter.Writer, in Thread) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -633,33 +896,43 @@ func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels6(out
 		}
 		out.Int(int(in.Votes))
 	}
+	if in.IsArchived {
+		const prefix string = ",\"isArchived\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.IsArchived))
+	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
 func (v Thread) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels6(&w, v)
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels9(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v Thread) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels6(w, v)
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels9(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *Thread) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels6(&r, v)
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels9(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *Thread) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels6(l, v)
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels9(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels7(in *jlexer.Lexer, out *Status) {
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels10(in *jlexer.Lexer, out *Status) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -696,7 +969,7 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels7(in *
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels7(out *jwriter.Writer, in Status) {
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels10(out *jwriter.Writer, in Status) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -726,27 +999,27 @@ func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels7(out
 // MarshalJSON supports json.Marshaler interface
 func (v Status) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels7(&w, v)
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels10(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v Status) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels7(w, v)
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels10(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *Status) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels7(&r, v)
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels10(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *Status) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels7(l, v)
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels10(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels8(in *jlexer.Lexer, out *RespError) {
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels11(in *jlexer.Lexer, out *Session) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -765,8 +1038,14 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels8(in *
 			continue
 		}
 		switch key {
-		case "message":
-			out.Message = string(in.String())
+		case "nickname":
+			out.Nickname = string(in.String())
+		case "token":
+			out.Token = string(in.String())
+		case "expires":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Expires).UnmarshalJSON(data))
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -777,42 +1056,52 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels8(in *
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels8(out *jwriter.Writer, in RespError) {
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels11(out *jwriter.Writer, in Session) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"message\":"
+		const prefix string = ",\"nickname\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Message))
+		out.String(string(in.Nickname))
+	}
+	{
+		const prefix string = ",\"token\":"
+		out.RawString(prefix)
+		out.String(string(in.Token))
+	}
+	{
+		const prefix string = ",\"expires\":"
+		out.RawString(prefix)
+		out.Raw((in.Expires).MarshalJSON())
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v RespError) MarshalJSON() ([]byte, error) {
+func (v Session) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels8(&w, v)
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels11(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v RespError) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels8(w, v)
+func (v Session) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels11(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *RespError) UnmarshalJSON(data []byte) error {
+func (v *Session) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels8(&r, v)
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels11(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *RespError) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels8(l, v)
+func (v *Session) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels11(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels9(in *jlexer.Lexer, out *PostUpdate) {
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels12(in *jlexer.Lexer, out *SearchResult) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -831,10 +1120,52 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels9(in *
 			continue
 		}
 		switch key {
-		case "id":
-			out.ID = int(in.Int())
-		case "message":
-			out.Message = string(in.String())
+		case "kind":
+			out.Kind = string(in.String())
+		case "rank":
+			out.Rank = float32(in.Float32())
+		case "snippet":
+			out.Snippet = string(in.String())
+		case "author":
+			if in.IsNull() {
+				in.Skip()
+				out.Author = nil
+			} else {
+				if out.Author == nil {
+					out.Author = new(User)
+				}
+				(*out.Author).UnmarshalEasyJSON(in)
+			}
+		case "forum":
+			if in.IsNull() {
+				in.Skip()
+				out.Forum = nil
+			} else {
+				if out.Forum == nil {
+					out.Forum = new(Forum)
+				}
+				(*out.Forum).UnmarshalEasyJSON(in)
+			}
+		case "post":
+			if in.IsNull() {
+				in.Skip()
+				out.Post = nil
+			} else {
+				if out.Post == nil {
+					out.Post = new(Post)
+				}
+				(*out.Post).UnmarshalEasyJSON(in)
+			}
+		case "thread":
+			if in.IsNull() {
+				in.Skip()
+				out.Thread = nil
+			} else {
+				if out.Thread == nil {
+					out.Thread = new(Thread)
+				}
+				(*out.Thread).UnmarshalEasyJSON(in)
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -845,47 +1176,72 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels9(in *
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels9(out *jwriter.Writer, in PostUpdate) {
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels12(out *jwriter.Writer, in SearchResult) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"id\":"
+		const prefix string = ",\"kind\":"
 		out.RawString(prefix[1:])
-		out.Int(int(in.ID))
+		out.String(string(in.Kind))
 	}
 	{
-		const prefix string = ",\"message\":"
+		const prefix string = ",\"rank\":"
 		out.RawString(prefix)
-		out.String(string(in.Message))
+		out.Float32(float32(in.Rank))
+	}
+	{
+		const prefix string = ",\"snippet\":"
+		out.RawString(prefix)
+		out.String(string(in.Snippet))
+	}
+	if in.Author != nil {
+		const prefix string = ",\"author\":"
+		out.RawString(prefix)
+		(*in.Author).MarshalEasyJSON(out)
+	}
+	if in.Forum != nil {
+		const prefix string = ",\"forum\":"
+		out.RawString(prefix)
+		(*in.Forum).MarshalEasyJSON(out)
+	}
+	if in.Post != nil {
+		const prefix string = ",\"post\":"
+		out.RawString(prefix)
+		(*in.Post).MarshalEasyJSON(out)
+	}
+	if in.Thread != nil {
+		const prefix string = ",\"thread\":"
+		out.RawString(prefix)
+		(*in.Thread).MarshalEasyJSON(out)
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v PostUpdate) MarshalJSON() ([]byte, error) {
+func (v SearchResult) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels9(&w, v)
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels12(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v PostUpdate) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels9(w, v)
+func (v SearchResult) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels12(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *PostUpdate) UnmarshalJSON(data []byte) error {
+func (v *SearchResult) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels9(&r, v)
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels12(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *PostUpdate) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels9(l, v)
+func (v *SearchResult) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels12(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels10(in *jlexer.Lexer, out *PostInput) {
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels13(in *jlexer.Lexer, out *SearchInput) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -904,8 +1260,20 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels10(in
 			continue
 		}
 		switch key {
-		case "id":
-			out.ID = int(in.Int())
+		case "Query":
+			out.Query = string(in.String())
+		case "Forum":
+			out.Forum = string(in.String())
+		case "Author":
+			out.Author = string(in.String())
+		case "Since":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Since).UnmarshalJSON(data))
+			}
+		case "Limit":
+			out.Limit = int(in.Int())
+		case "Related":
+			out.Related = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -916,42 +1284,67 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels10(in
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels10(out *jwriter.Writer, in PostInput) {
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels13(out *jwriter.Writer, in SearchInput) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"id\":"
+		const prefix string = ",\"Query\":"
 		out.RawString(prefix[1:])
-		out.Int(int(in.ID))
+		out.String(string(in.Query))
+	}
+	{
+		const prefix string = ",\"Forum\":"
+		out.RawString(prefix)
+		out.String(string(in.Forum))
+	}
+	{
+		const prefix string = ",\"Author\":"
+		out.RawString(prefix)
+		out.String(string(in.Author))
+	}
+	{
+		const prefix string = ",\"Since\":"
+		out.RawString(prefix)
+		out.Raw((in.Since).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"Limit\":"
+		out.RawString(prefix)
+		out.Int(int(in.Limit))
+	}
+	{
+		const prefix string = ",\"Related\":"
+		out.RawString(prefix)
+		out.String(string(in.Related))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v PostInput) MarshalJSON() ([]byte, error) {
+func (v SearchInput) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels10(&w, v)
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels13(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v PostInput) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels10(w, v)
+func (v SearchInput) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels13(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *PostInput) UnmarshalJSON(data []byte) error {
+func (v *SearchInput) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels10(&r, v)
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels13(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *PostInput) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels10(l, v)
+func (v *SearchInput) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels13(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels11(in *jlexer.Lexer, out *PostFull) {
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels14(in *jlexer.Lexer, out *RoleChange) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -970,46 +1363,8 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels11(in
 			continue
 		}
 		switch key {
-		case "author":
-			if in.IsNull() {
-				in.Skip()
-				out.Author = nil
-			} else {
-				if out.Author == nil {
-					out.Author = new(User)
-				}
-				(*out.Author).UnmarshalEasyJSON(in)
-			}
-		case "forum":
-			if in.IsNull() {
-				in.Skip()
-				out.Forum = nil
-			} else {
-				if out.Forum == nil {
-					out.Forum = new(Forum)
-				}
-				(*out.Forum).UnmarshalEasyJSON(in)
-			}
-		case "post":
-			if in.IsNull() {
-				in.Skip()
-				out.Post = nil
-			} else {
-				if out.Post == nil {
-					out.Post = new(Post)
-				}
-				(*out.Post).UnmarshalEasyJSON(in)
-			}
-		case "thread":
-			if in.IsNull() {
-				in.Skip()
-				out.Thread = nil
-			} else {
-				if out.Thread == nil {
-					out.Thread = new(Thread)
-				}
-				(*out.Thread).UnmarshalEasyJSON(in)
-			}
+		case "role":
+			out.Role = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -1020,73 +1375,42 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels11(in
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels11(out *jwriter.Writer, in PostFull) {
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels14(out *jwriter.Writer, in RoleChange) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Author != nil {
-		const prefix string = ",\"author\":"
-		first = false
+	{
+		const prefix string = ",\"role\":"
 		out.RawString(prefix[1:])
-		(*in.Author).MarshalEasyJSON(out)
-	}
-	if in.Forum != nil {
-		const prefix string = ",\"forum\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		(*in.Forum).MarshalEasyJSON(out)
-	}
-	if in.Post != nil {
-		const prefix string = ",\"post\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		(*in.Post).MarshalEasyJSON(out)
-	}
-	if in.Thread != nil {
-		const prefix string = ",\"thread\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		(*in.Thread).MarshalEasyJSON(out)
+		out.String(string(in.Role))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v PostFull) MarshalJSON() ([]byte, error) {
+func (v RoleChange) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels11(&w, v)
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels14(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v PostFull) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels11(w, v)
+func (v RoleChange) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels14(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *PostFull) UnmarshalJSON(data []byte) error {
+func (v *RoleChange) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels11(&r, v)
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels14(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *PostFull) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels11(l, v)
+func (v *RoleChange) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels14(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels12(in *jlexer.Lexer, out *PostCreate) {
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels15(in *jlexer.Lexer, out *RespError) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1105,10 +1429,6 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels12(in
 			continue
 		}
 		switch key {
-		case "parent":
-			out.Parent = int(in.Int())
-		case "author":
-			out.Author = string(in.String())
 		case "message":
 			out.Message = string(in.String())
 		default:
@@ -1121,63 +1441,42 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels12(in
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels12(out *jwriter.Writer, in PostCreate) {
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels15(out *jwriter.Writer, in RespError) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Parent != 0 {
-		const prefix string = ",\"parent\":"
-		first = false
-		out.RawString(prefix[1:])
-		out.Int(int(in.Parent))
-	}
-	if in.Author != "" {
-		const prefix string = ",\"author\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Author))
-	}
-	if in.Message != "" {
+	{
 		const prefix string = ",\"message\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
+		out.RawString(prefix[1:])
 		out.String(string(in.Message))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v PostCreate) MarshalJSON() ([]byte, error) {
+func (v RespError) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels12(&w, v)
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels15(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v PostCreate) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels12(w, v)
+func (v RespError) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels15(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *PostCreate) UnmarshalJSON(data []byte) error {
+func (v *RespError) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels12(&r, v)
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels15(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *PostCreate) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels12(l, v)
+func (v *RespError) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels15(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels13(in *jlexer.Lexer, out *Post) {
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels16(in *jlexer.Lexer, out *Reconciliation) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1196,22 +1495,31 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels13(in
 			continue
 		}
 		switch key {
-		case "id":
-			out.ID = int(in.Int())
-		case "parent":
-			out.Parent = int(in.Int())
-		case "author":
-			out.Author = string(in.String())
-		case "message":
-			out.Message = string(in.String())
-		case "isEdited":
-			out.IsEdited = bool(in.Bool())
-		case "forum":
-			out.Forum = string(in.String())
-		case "created":
-			out.Created = string(in.String())
-		case "thread":
-			out.ThreadID = int(in.Int())
+		case "fixed":
+			out.Fixed = bool(in.Bool())
+		case "drifts":
+			if in.IsNull() {
+				in.Skip()
+				out.Drifts = nil
+			} else {
+				in.Delim('[')
+				if out.Drifts == nil {
+					if !in.IsDelim(']') {
+						out.Drifts = make([]CounterDrift, 0, 1)
+					} else {
+						out.Drifts = []CounterDrift{}
+					}
+				} else {
+					out.Drifts = (out.Drifts)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 CounterDrift
+					(v1).UnmarshalEasyJSON(in)
+					out.Drifts = append(out.Drifts, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -1222,113 +1530,1680 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels13(in
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels13(out *jwriter.Writer, in Post) {
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels16(out *jwriter.Writer, in Reconciliation) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.ID != 0 {
-		const prefix string = ",\"id\":"
-		first = false
+	{
+		const prefix string = ",\"fixed\":"
 		out.RawString(prefix[1:])
-		out.Int(int(in.ID))
+		out.Bool(bool(in.Fixed))
 	}
-	if in.Parent != 0 {
-		const prefix string = ",\"parent\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
+	{
+		const prefix string = ",\"drifts\":"
+		out.RawString(prefix)
+		if in.Drifts == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
 		} else {
-			out.RawString(prefix)
+			out.RawByte('[')
+			for v2, v3 := range in.Drifts {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				(v3).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Reconciliation) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels16(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Reconciliation) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels16(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Reconciliation) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels16(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Reconciliation) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels16(l, v)
+}
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels17(in *jlexer.Lexer, out *PostUpdate) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = int(in.Int())
+		case "message":
+			out.Message = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels17(out *jwriter.Writer, in PostUpdate) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.ID))
+	}
+	{
+		const prefix string = ",\"message\":"
+		out.RawString(prefix)
+		out.String(string(in.Message))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PostUpdate) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels17(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PostUpdate) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels17(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PostUpdate) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels17(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PostUpdate) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels17(l, v)
+}
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels18(in *jlexer.Lexer, out *PostRevision) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "revision":
+			out.Revision = int(in.Int())
+		case "message":
+			out.Message = string(in.String())
+		case "editor":
+			out.Editor = string(in.String())
+		case "created":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Created).UnmarshalJSON(data))
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels18(out *jwriter.Writer, in PostRevision) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"revision\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Revision))
+	}
+	{
+		const prefix string = ",\"message\":"
+		out.RawString(prefix)
+		out.String(string(in.Message))
+	}
+	{
+		const prefix string = ",\"editor\":"
+		out.RawString(prefix)
+		out.String(string(in.Editor))
+	}
+	{
+		const prefix string = ",\"created\":"
+		out.RawString(prefix)
+		out.Raw((in.Created).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PostRevision) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels18(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PostRevision) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels18(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PostRevision) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels18(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PostRevision) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels18(l, v)
+}
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels19(in *jlexer.Lexer, out *PostInput) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels19(out *jwriter.Writer, in PostInput) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.ID))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PostInput) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels19(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PostInput) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels19(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PostInput) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels19(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PostInput) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels19(l, v)
+}
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels20(in *jlexer.Lexer, out *PostFull) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "author":
+			if in.IsNull() {
+				in.Skip()
+				out.Author = nil
+			} else {
+				if out.Author == nil {
+					out.Author = new(User)
+				}
+				(*out.Author).UnmarshalEasyJSON(in)
+			}
+		case "forum":
+			if in.IsNull() {
+				in.Skip()
+				out.Forum = nil
+			} else {
+				if out.Forum == nil {
+					out.Forum = new(Forum)
+				}
+				(*out.Forum).UnmarshalEasyJSON(in)
+			}
+		case "post":
+			if in.IsNull() {
+				in.Skip()
+				out.Post = nil
+			} else {
+				if out.Post == nil {
+					out.Post = new(Post)
+				}
+				(*out.Post).UnmarshalEasyJSON(in)
+			}
+		case "thread":
+			if in.IsNull() {
+				in.Skip()
+				out.Thread = nil
+			} else {
+				if out.Thread == nil {
+					out.Thread = new(Thread)
+				}
+				(*out.Thread).UnmarshalEasyJSON(in)
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels20(out *jwriter.Writer, in PostFull) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Author != nil {
+		const prefix string = ",\"author\":"
+		first = false
+		out.RawString(prefix[1:])
+		(*in.Author).MarshalEasyJSON(out)
+	}
+	if in.Forum != nil {
+		const prefix string = ",\"forum\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		(*in.Forum).MarshalEasyJSON(out)
+	}
+	if in.Post != nil {
+		const prefix string = ",\"post\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		(*in.Post).MarshalEasyJSON(out)
+	}
+	if in.Thread != nil {
+		const prefix string = ",\"thread\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		(*in.Thread).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PostFull) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels20(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PostFull) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels20(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PostFull) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels20(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PostFull) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels20(l, v)
+}
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels21(in *jlexer.Lexer, out *PostDiff) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "post":
+			out.Post = int(in.Int())
+		case "from":
+			(out.From).UnmarshalEasyJSON(in)
+		case "to":
+			(out.To).UnmarshalEasyJSON(in)
+		case "lines":
+			if in.IsNull() {
+				in.Skip()
+				out.Lines = nil
+			} else {
+				in.Delim('[')
+				if out.Lines == nil {
+					if !in.IsDelim(']') {
+						out.Lines = make([]DiffLine, 0, 2)
+					} else {
+						out.Lines = []DiffLine{}
+					}
+				} else {
+					out.Lines = (out.Lines)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v4 DiffLine
+					(v4).UnmarshalEasyJSON(in)
+					out.Lines = append(out.Lines, v4)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels21(out *jwriter.Writer, in PostDiff) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"post\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Post))
+	}
+	{
+		const prefix string = ",\"from\":"
+		out.RawString(prefix)
+		(in.From).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"to\":"
+		out.RawString(prefix)
+		(in.To).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"lines\":"
+		out.RawString(prefix)
+		if in.Lines == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v5, v6 := range in.Lines {
+				if v5 > 0 {
+					out.RawByte(',')
+				}
+				(v6).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PostDiff) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels21(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PostDiff) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels21(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PostDiff) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels21(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PostDiff) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels21(l, v)
+}
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels22(in *jlexer.Lexer, out *PostCreate) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "parent":
+			out.Parent = int(in.Int())
+		case "author":
+			out.Author = string(in.String())
+		case "message":
+			out.Message = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels22(out *jwriter.Writer, in PostCreate) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Parent != 0 {
+		const prefix string = ",\"parent\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.Int(int(in.Parent))
+	}
+	if in.Author != "" {
+		const prefix string = ",\"author\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Author))
+	}
+	if in.Message != "" {
+		const prefix string = ",\"message\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Message))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PostCreate) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels22(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PostCreate) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels22(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PostCreate) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels22(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PostCreate) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels22(l, v)
+}
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels23(in *jlexer.Lexer, out *Post) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = int(in.Int())
+		case "parent":
+			out.Parent = int(in.Int())
+		case "author":
+			out.Author = string(in.String())
+		case "message":
+			out.Message = string(in.String())
+		case "isEdited":
+			out.IsEdited = bool(in.Bool())
+		case "isDeleted":
+			out.IsDeleted = bool(in.Bool())
+		case "votes":
+			out.Votes = int(in.Int())
+		case "forum":
+			out.Forum = string(in.String())
+		case "created":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Created).UnmarshalJSON(data))
+			}
+		case "thread":
+			out.ThreadID = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels23(out *jwriter.Writer, in Post) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.ID != 0 {
+		const prefix string = ",\"id\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.Int(int(in.ID))
+	}
+	if in.Parent != 0 {
+		const prefix string = ",\"parent\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Parent))
+	}
+	if in.Author != "" {
+		const prefix string = ",\"author\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Author))
+	}
+	if in.Message != "" {
+		const prefix string = ",\"message\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Message))
+	}
+	if in.IsEdited {
+		const prefix string = ",\"isEdited\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.IsEdited))
+	}
+	if in.IsDeleted {
+		const prefix string = ",\"isDeleted\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.IsDeleted))
+	}
+	if in.Votes != 0 {
+		const prefix string = ",\"votes\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Votes))
+	}
+	if in.Forum != "" {
+		const prefix string = ",\"forum\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Forum))
+	}
+	if true {
+		const prefix string = ",\"created\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Raw((in.Created).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"thread\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.ThreadID))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Post) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels23(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Post) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels23(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Post) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels23(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Post) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels23(l, v)
+}
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels24(in *jlexer.Lexer, out *PasswordChange) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "password":
+			out.Password = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels24(out *jwriter.Writer, in PasswordChange) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"password\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Password))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PasswordChange) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels24(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PasswordChange) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels24(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PasswordChange) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels24(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PasswordChange) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels24(l, v)
+}
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels25(in *jlexer.Lexer, out *HealthCheck) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "name":
+			out.Name = string(in.String())
+		case "status":
+			out.Status = string(in.String())
+		case "detail":
+			out.Detail = string(in.String())
+		case "error":
+			out.Error = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels25(out *jwriter.Writer, in HealthCheck) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"name\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Name))
+	}
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix)
+		out.String(string(in.Status))
+	}
+	if in.Detail != "" {
+		const prefix string = ",\"detail\":"
+		out.RawString(prefix)
+		out.String(string(in.Detail))
+	}
+	if in.Error != "" {
+		const prefix string = ",\"error\":"
+		out.RawString(prefix)
+		out.String(string(in.Error))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v HealthCheck) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels25(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v HealthCheck) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels25(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *HealthCheck) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels25(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *HealthCheck) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels25(l, v)
+}
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels26(in *jlexer.Lexer, out *Health) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "status":
+			out.Status = string(in.String())
+		case "checks":
+			if in.IsNull() {
+				in.Skip()
+				out.Checks = nil
+			} else {
+				in.Delim('[')
+				if out.Checks == nil {
+					if !in.IsDelim(']') {
+						out.Checks = make([]HealthCheck, 0, 1)
+					} else {
+						out.Checks = []HealthCheck{}
+					}
+				} else {
+					out.Checks = (out.Checks)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v7 HealthCheck
+					(v7).UnmarshalEasyJSON(in)
+					out.Checks = append(out.Checks, v7)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels26(out *jwriter.Writer, in Health) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Status))
+	}
+	if len(in.Checks) != 0 {
+		const prefix string = ",\"checks\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v8, v9 := range in.Checks {
+				if v8 > 0 {
+					out.RawByte(',')
+				}
+				(v9).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Health) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels26(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Health) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels26(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Health) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels26(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Health) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels26(l, v)
+}
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels27(in *jlexer.Lexer, out *ForumList) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "Limit":
+			out.Limit = int(in.Int())
+		case "Since":
+			out.Since = string(in.String())
+		case "Sort":
+			out.Sort = string(in.String())
+		case "Desc":
+			out.Desc = bool(in.Bool())
+		case "Title":
+			out.Title = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels27(out *jwriter.Writer, in ForumList) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"Limit\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Limit))
+	}
+	{
+		const prefix string = ",\"Since\":"
+		out.RawString(prefix)
+		out.String(string(in.Since))
+	}
+	{
+		const prefix string = ",\"Sort\":"
+		out.RawString(prefix)
+		out.String(string(in.Sort))
+	}
+	{
+		const prefix string = ",\"Desc\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Desc))
+	}
+	{
+		const prefix string = ",\"Title\":"
+		out.RawString(prefix)
+		out.String(string(in.Title))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ForumList) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels27(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ForumList) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels27(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ForumList) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels27(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ForumList) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels27(l, v)
+}
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels28(in *jlexer.Lexer, out *ForumInput) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "Slug":
+			out.Slug = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels28(out *jwriter.Writer, in ForumInput) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"Slug\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Slug))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ForumInput) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels28(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ForumInput) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels28(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ForumInput) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels28(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ForumInput) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels28(l, v)
+}
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels29(in *jlexer.Lexer, out *ForumGetUsers) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "Slug":
+			out.Slug = string(in.String())
+		case "Limit":
+			out.Limit = int(in.Int())
+		case "Since":
+			out.Since = string(in.String())
+		case "Desc":
+			out.Desc = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels29(out *jwriter.Writer, in ForumGetUsers) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"Slug\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Slug))
+	}
+	{
+		const prefix string = ",\"Limit\":"
+		out.RawString(prefix)
+		out.Int(int(in.Limit))
+	}
+	{
+		const prefix string = ",\"Since\":"
+		out.RawString(prefix)
+		out.String(string(in.Since))
+	}
+	{
+		const prefix string = ",\"Desc\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Desc))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ForumGetUsers) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels29(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ForumGetUsers) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels29(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ForumGetUsers) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels29(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ForumGetUsers) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels29(l, v)
+}
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels30(in *jlexer.Lexer, out *ForumGetThreads) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
 		}
-		out.Int(int(in.Parent))
+		switch key {
+		case "Slug":
+			out.Slug = string(in.String())
+		case "Limit":
+			out.Limit = int(in.Int())
+		case "Since":
+			out.Since = string(in.String())
+		case "Desc":
+			out.Desc = bool(in.Bool())
+		case "Archived":
+			out.Archived = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
 	}
-	if in.Author != "" {
-		const prefix string = ",\"author\":"
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels30(out *jwriter.Writer, in ForumGetThreads) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"Slug\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Slug))
+	}
+	{
+		const prefix string = ",\"Limit\":"
+		out.RawString(prefix)
+		out.Int(int(in.Limit))
+	}
+	{
+		const prefix string = ",\"Since\":"
+		out.RawString(prefix)
+		out.String(string(in.Since))
+	}
+	{
+		const prefix string = ",\"Desc\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Desc))
+	}
+	{
+		const prefix string = ",\"Archived\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Archived))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ForumGetThreads) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels30(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ForumGetThreads) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels30(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ForumGetThreads) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels30(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ForumGetThreads) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels30(l, v)
+}
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels31(in *jlexer.Lexer, out *ForumCreate) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "slug":
+			out.Slug = string(in.String())
+		case "title":
+			out.Title = string(in.String())
+		case "user":
+			out.User = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels31(out *jwriter.Writer, in ForumCreate) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"slug\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Slug))
+	}
+	{
+		const prefix string = ",\"title\":"
+		out.RawString(prefix)
+		out.String(string(in.Title))
+	}
+	{
+		const prefix string = ",\"user\":"
+		out.RawString(prefix)
+		out.String(string(in.User))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ForumCreate) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels31(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ForumCreate) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels31(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ForumCreate) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels31(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ForumCreate) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels31(l, v)
+}
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels32(in *jlexer.Lexer, out *Forum) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "slug":
+			out.Slug = string(in.String())
+		case "title":
+			out.Title = string(in.String())
+		case "user":
+			out.User = string(in.String())
+		case "threads":
+			out.Threads = int(in.Int())
+		case "posts":
+			out.Posts = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels32(out *jwriter.Writer, in Forum) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Slug != "" {
+		const prefix string = ",\"slug\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Slug))
+	}
+	if in.Title != "" {
+		const prefix string = ",\"title\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Author))
+		out.String(string(in.Title))
 	}
-	if in.Message != "" {
-		const prefix string = ",\"message\":"
+	if in.User != "" {
+		const prefix string = ",\"user\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Message))
+		out.String(string(in.User))
 	}
-	if in.IsEdited {
-		const prefix string = ",\"isEdited\":"
+	if in.Threads != 0 {
+		const prefix string = ",\"threads\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Bool(bool(in.IsEdited))
+		out.Int(int(in.Threads))
 	}
-	if in.Forum != "" {
-		const prefix string = ",\"forum\":"
+	if in.Posts != 0 {
+		const prefix string = ",\"posts\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Forum))
-	}
-	if in.Created != "" {
-		const prefix string = ",\"created\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
+		out.Int(int(in.Posts))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Forum) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels32(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Forum) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels32(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Forum) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels32(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Forum) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels32(l, v)
+}
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels33(in *jlexer.Lexer, out *FieldError) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "field":
+			out.Field = string(in.String())
+		case "code":
+			out.Code = string(in.String())
+		case "message":
+			out.Message = string(in.String())
+		default:
+			in.SkipRecursive()
 		}
-		out.String(string(in.Created))
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
 	}
+}
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels33(out *jwriter.Writer, in FieldError) {
+	out.RawByte('{')
+	first := true
+	_ = first
 	{
-		const prefix string = ",\"thread\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.Int(int(in.ThreadID))
+		const prefix string = ",\"field\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Field))
+	}
+	{
+		const prefix string = ",\"code\":"
+		out.RawString(prefix)
+		out.String(string(in.Code))
+	}
+	{
+		const prefix string = ",\"message\":"
+		out.RawString(prefix)
+		out.String(string(in.Message))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Post) MarshalJSON() ([]byte, error) {
+func (v FieldError) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels13(&w, v)
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels33(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Post) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels13(w, v)
+func (v FieldError) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels33(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Post) UnmarshalJSON(data []byte) error {
+func (v *FieldError) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels13(&r, v)
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels33(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Post) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels13(l, v)
+func (v *FieldError) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels33(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels14(in *jlexer.Lexer, out *ForumInput) {
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels34(in *jlexer.Lexer, out *Error) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1347,8 +3222,33 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels14(in
 			continue
 		}
 		switch key {
-		case "Slug":
-			out.Slug = string(in.String())
+		case "code":
+			out.Code = string(in.String())
+		case "message":
+			out.Message = string(in.String())
+		case "fields":
+			if in.IsNull() {
+				in.Skip()
+				out.Fields = nil
+			} else {
+				in.Delim('[')
+				if out.Fields == nil {
+					if !in.IsDelim(']') {
+						out.Fields = make([]FieldError, 0, 1)
+					} else {
+						out.Fields = []FieldError{}
+					}
+				} else {
+					out.Fields = (out.Fields)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v10 FieldError
+					(v10).UnmarshalEasyJSON(in)
+					out.Fields = append(out.Fields, v10)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -1359,42 +3259,66 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels14(in
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels14(out *jwriter.Writer, in ForumInput) {
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels34(out *jwriter.Writer, in Error) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"Slug\":"
-		out.RawString(prefix[1:])
-		out.String(string(in.Slug))
+		const prefix string = ",\"code\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Code))
+	}
+	{
+		const prefix string = ",\"message\":"
+		out.RawString(prefix)
+		out.String(string(in.Message))
+	}
+	if len(in.Fields) != 0 {
+		const prefix string = ",\"fields\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v11, v12 := range in.Fields {
+				if v11 > 0 {
+					out.RawByte(',')
+				}
+				(v12).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v ForumInput) MarshalJSON() ([]byte, error) {
+func (v Error) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels14(&w, v)
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels34(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ForumInput) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels14(w, v)
+func (v Error) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels34(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *ForumInput) UnmarshalJSON(data []byte) error {
+func (v *Error) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels14(&r, v)
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels34(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ForumInput) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels14(l, v)
+func (v *Error) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels34(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels15(in *jlexer.Lexer, out *ForumGetUsers) {
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels35(in *jlexer.Lexer, out *DiffLine) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1413,14 +3337,10 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels15(in
 			continue
 		}
 		switch key {
-		case "Slug":
-			out.Slug = string(in.String())
-		case "Limit":
-			out.Limit = int(in.Int())
-		case "Since":
-			out.Since = string(in.String())
-		case "Desc":
-			out.Desc = bool(in.Bool())
+		case "op":
+			out.Op = string(in.String())
+		case "text":
+			out.Text = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -1431,57 +3351,47 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels15(in
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels15(out *jwriter.Writer, in ForumGetUsers) {
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels35(out *jwriter.Writer, in DiffLine) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"Slug\":"
+		const prefix string = ",\"op\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Slug))
-	}
-	{
-		const prefix string = ",\"Limit\":"
-		out.RawString(prefix)
-		out.Int(int(in.Limit))
-	}
-	{
-		const prefix string = ",\"Since\":"
-		out.RawString(prefix)
-		out.String(string(in.Since))
+		out.String(string(in.Op))
 	}
 	{
-		const prefix string = ",\"Desc\":"
+		const prefix string = ",\"text\":"
 		out.RawString(prefix)
-		out.Bool(bool(in.Desc))
+		out.String(string(in.Text))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v ForumGetUsers) MarshalJSON() ([]byte, error) {
+func (v DiffLine) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels15(&w, v)
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels35(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ForumGetUsers) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels15(w, v)
+func (v DiffLine) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels35(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *ForumGetUsers) UnmarshalJSON(data []byte) error {
+func (v *DiffLine) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels15(&r, v)
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels35(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ForumGetUsers) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels15(l, v)
+func (v *DiffLine) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels35(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels16(in *jlexer.Lexer, out *ForumGetThreads) {
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels36(in *jlexer.Lexer, out *Credentials) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1500,14 +3410,10 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels16(in
 			continue
 		}
 		switch key {
-		case "Slug":
-			out.Slug = string(in.String())
-		case "Limit":
-			out.Limit = int(in.Int())
-		case "Since":
-			out.Since = string(in.String())
-		case "Desc":
-			out.Desc = bool(in.Bool())
+		case "nickname":
+			out.Nickname = string(in.String())
+		case "password":
+			out.Password = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -1518,57 +3424,47 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels16(in
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels16(out *jwriter.Writer, in ForumGetThreads) {
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels36(out *jwriter.Writer, in Credentials) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"Slug\":"
+		const prefix string = ",\"nickname\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Slug))
-	}
-	{
-		const prefix string = ",\"Limit\":"
-		out.RawString(prefix)
-		out.Int(int(in.Limit))
-	}
-	{
-		const prefix string = ",\"Since\":"
-		out.RawString(prefix)
-		out.String(string(in.Since))
+		out.String(string(in.Nickname))
 	}
 	{
-		const prefix string = ",\"Desc\":"
+		const prefix string = ",\"password\":"
 		out.RawString(prefix)
-		out.Bool(bool(in.Desc))
+		out.String(string(in.Password))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v ForumGetThreads) MarshalJSON() ([]byte, error) {
+func (v Credentials) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels16(&w, v)
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels36(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ForumGetThreads) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels16(w, v)
+func (v Credentials) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels36(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *ForumGetThreads) UnmarshalJSON(data []byte) error {
+func (v *Credentials) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels16(&r, v)
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels36(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ForumGetThreads) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels16(l, v)
+func (v *Credentials) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels36(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels17(in *jlexer.Lexer, out *ForumCreate) {
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels37(in *jlexer.Lexer, out *CounterDrift) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1587,12 +3483,18 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels17(in
 			continue
 		}
 		switch key {
-		case "slug":
-			out.Slug = string(in.String())
-		case "title":
-			out.Title = string(in.String())
-		case "user":
-			out.User = string(in.String())
+		case "counter":
+			out.Counter = string(in.String())
+		case "forum":
+			out.Forum = string(in.String())
+		case "thread":
+			out.Thread = int(in.Int())
+		case "post":
+			out.Post = int(in.Int())
+		case "stored":
+			out.Stored = int(in.Int())
+		case "actual":
+			out.Actual = int(in.Int())
 		default:
 			in.SkipRecursive()
 		}
@@ -1603,52 +3505,67 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels17(in
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels17(out *jwriter.Writer, in ForumCreate) {
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels37(out *jwriter.Writer, in CounterDrift) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"slug\":"
+		const prefix string = ",\"counter\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Slug))
+		out.String(string(in.Counter))
 	}
 	{
-		const prefix string = ",\"title\":"
+		const prefix string = ",\"forum\":"
 		out.RawString(prefix)
-		out.String(string(in.Title))
+		out.String(string(in.Forum))
+	}
+	if in.Thread != 0 {
+		const prefix string = ",\"thread\":"
+		out.RawString(prefix)
+		out.Int(int(in.Thread))
+	}
+	if in.Post != 0 {
+		const prefix string = ",\"post\":"
+		out.RawString(prefix)
+		out.Int(int(in.Post))
 	}
 	{
-		const prefix string = ",\"user\":"
+		const prefix string = ",\"stored\":"
 		out.RawString(prefix)
-		out.String(string(in.User))
+		out.Int(int(in.Stored))
+	}
+	{
+		const prefix string = ",\"actual\":"
+		out.RawString(prefix)
+		out.Int(int(in.Actual))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v ForumCreate) MarshalJSON() ([]byte, error) {
+func (v CounterDrift) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels17(&w, v)
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels37(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ForumCreate) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels17(w, v)
+func (v CounterDrift) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels37(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *ForumCreate) UnmarshalJSON(data []byte) error {
+func (v *CounterDrift) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels17(&r, v)
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels37(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ForumCreate) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels17(l, v)
+func (v *CounterDrift) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels37(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels18(in *jlexer.Lexer, out *Forum) {
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels38(in *jlexer.Lexer, out *ClearInput) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1667,16 +3584,10 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels18(in
 			continue
 		}
 		switch key {
-		case "slug":
-			out.Slug = string(in.String())
-		case "title":
-			out.Title = string(in.String())
-		case "user":
-			out.User = string(in.String())
-		case "threads":
-			out.Threads = int(in.Int())
-		case "posts":
-			out.Posts = int(in.Int())
+		case "Forum":
+			out.Forum = string(in.String())
+		case "Confirm":
+			out.Confirm = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -1687,83 +3598,47 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels18(in
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels18(out *jwriter.Writer, in Forum) {
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels38(out *jwriter.Writer, in ClearInput) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Slug != "" {
-		const prefix string = ",\"slug\":"
-		first = false
+	{
+		const prefix string = ",\"Forum\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Slug))
-	}
-	if in.Title != "" {
-		const prefix string = ",\"title\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Title))
-	}
-	if in.User != "" {
-		const prefix string = ",\"user\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.User))
-	}
-	if in.Threads != 0 {
-		const prefix string = ",\"threads\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.Int(int(in.Threads))
+		out.String(string(in.Forum))
 	}
-	if in.Posts != 0 {
-		const prefix string = ",\"posts\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.Int(int(in.Posts))
+	{
+		const prefix string = ",\"Confirm\":"
+		out.RawString(prefix)
+		out.String(string(in.Confirm))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Forum) MarshalJSON() ([]byte, error) {
+func (v ClearInput) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels18(&w, v)
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels38(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Forum) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels18(w, v)
+func (v ClearInput) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels38(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Forum) UnmarshalJSON(data []byte) error {
+func (v *ClearInput) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels18(&r, v)
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels38(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Forum) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels18(l, v)
+func (v *ClearInput) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels38(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels19(in *jlexer.Lexer, out *Error) {
+func easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels39(in *jlexer.Lexer, out *ClearConfirmation) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1782,8 +3657,14 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels19(in
 			continue
 		}
 		switch key {
-		case "message":
-			out.Message = string(in.String())
+		case "confirm":
+			out.Confirm = string(in.String())
+		case "forum":
+			out.Forum = string(in.String())
+		case "expires":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Expires).UnmarshalJSON(data))
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -1794,43 +3675,48 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels19(in
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels19(out *jwriter.Writer, in Error) {
+func easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels39(out *jwriter.Writer, in ClearConfirmation) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"message\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Message))
+		const prefix string = ",\"confirm\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Confirm))
+	}
+	if in.Forum != "" {
+		const prefix string = ",\"forum\":"
+		out.RawString(prefix)
+		out.String(string(in.Forum))
+	}
+	{
+		const prefix string = ",\"expires\":"
+		out.RawString(prefix)
+		out.Raw((in.Expires).MarshalJSON())
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Error) MarshalJSON() ([]byte, error) {
+func (v ClearConfirmation) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels19(&w, v)
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels39(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Error) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels19(w, v)
+func (v ClearConfirmation) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComPringleskateTpDbForumInternalModels39(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Error) UnmarshalJSON(data []byte) error {
+func (v *ClearConfirmation) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels19(&r, v)
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels39(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Error) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels19(l, v)
+func (v *ClearConfirmation) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComPringleskateTpDbForumInternalModels39(l, v)
 }
@@ -0,0 +1,235 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+/* machine readable reasons in FieldError.Code */
+const (
+	InvalidRequired = "required"
+	InvalidFormat   = "format"
+	InvalidLength   = "length"
+	InvalidValue    = "value"
+)
+
+/* limits in characters, except passwords: bcrypt ignores everything past 72 bytes, so longer ones are refused rather than silently truncated */
+const (
+	MaxNicknameLength = 64
+	MaxSlugLength     = 128
+	MaxTitleLength    = 512
+	MaxFullnameLength = 256
+	MaxAboutLength    = 8192
+	MaxMessageLength  = 65536
+	MaxPasswordLength = 72
+)
+
+var (
+	nicknameFormat = regexp.MustCompile(`^[A-Za-z0-9_.]+$`)
+	slugFormat     = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+	digitsOnly     = regexp.MustCompile(`^[0-9]+$`)
+	emailFormat    = regexp.MustCompile(`^[^@\s]+@[^@\s]+$`)
+)
+
+/* collects every problem of an input instead of stopping at the first one */
+type validator struct {
+	prefix string
+	fields []FieldError
+}
+
+func (v *validator) add(field string, code string, message string) {
+	v.fields = append(v.fields, FieldError{Field: v.prefix + field, Code: code, Message: message})
+}
+
+func (v *validator) required(field string, value string) bool {
+	if strings.TrimSpace(value) == "" {
+		v.add(field, InvalidRequired, field+" is required")
+		return false
+	}
+	return true
+}
+
+func (v *validator) maxLength(field string, value string, max int) {
+	if utf8.RuneCountInString(value) > max {
+		v.add(field, InvalidLength, fmt.Sprintf("%s must be at most %d characters", field, max))
+	}
+}
+
+/* empty values pass, combine with required where the field is mandatory */
+func (v *validator) nickname(field string, value string) {
+	if value == "" {
+		return
+	}
+	v.maxLength(field, value, MaxNicknameLength)
+	if !nicknameFormat.MatchString(value) {
+		v.add(field, InvalidFormat, field+" may contain only latin letters, digits, '_' and '.'")
+	}
+}
+
+/* slugs share the URL segment with numeric IDs, so a slug made of digits only would be ambiguous */
+func (v *validator) slug(field string, value string) {
+	if value == "" {
+		return
+	}
+	v.maxLength(field, value, MaxSlugLength)
+	if !slugFormat.MatchString(value) || digitsOnly.MatchString(value) {
+		v.add(field, InvalidFormat, field+" may contain only latin letters, digits, '_' and '-' and must not be a number")
+	}
+}
+
+func (v *validator) email(field string, value string) {
+	if value != "" && !emailFormat.MatchString(value) {
+		v.add(field, InvalidFormat, field+" is not a valid email address")
+	}
+}
+
+func (v *validator) password(field string, value string) {
+	if len(value) > MaxPasswordLength {
+		v.add(field, InvalidLength, fmt.Sprintf("%s must be at most %d bytes", field, MaxPasswordLength))
+	}
+}
+
+func (v *validator) err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return ErrValidation.WithFields(v.fields)
+}
+
+/* the JSON itself could not be decoded into the input model */
+func MalformedBody(err error) error {
+	return ErrValidation.WithCode("malformed_body").WithMessage("malformed request body: " + err.Error())
+}
+
+func (f ForumCreate) Validate() error {
+	v := &validator{}
+	if v.required("slug", f.Slug) {
+		v.slug("slug", f.Slug)
+	}
+	if v.required("title", f.Title) {
+		v.maxLength("title", f.Title, MaxTitleLength)
+	}
+	if v.required("user", f.User) {
+		v.nickname("user", f.User)
+	}
+	return v.err()
+}
+
+/* profile creation, every field is mandatory */
+func (u User) Validate() error {
+	v := &validator{}
+	if v.required("nickname", u.Nickname) {
+		v.nickname("nickname", u.Nickname)
+	}
+	if v.required("fullname", u.Fullname) {
+		v.maxLength("fullname", u.Fullname, MaxFullnameLength)
+	}
+	if v.required("email", u.Email) {
+		v.email("email", u.Email)
+	}
+	v.maxLength("about", u.About, MaxAboutLength)
+	return v.err()
+}
+
+/* profile update, omitted fields keep their value */
+func (u User) ValidateUpdate() error {
+	v := &validator{}
+	v.maxLength("fullname", u.Fullname, MaxFullnameLength)
+	v.email("email", u.Email)
+	v.maxLength("about", u.About, MaxAboutLength)
+	return v.err()
+}
+
+func (c Credentials) Validate() error {
+	v := &validator{}
+	if v.required("nickname", c.Nickname) {
+		v.nickname("nickname", c.Nickname)
+	}
+	if v.required("password", c.Password) {
+		v.password("password", c.Password)
+	}
+	return v.err()
+}
+
+func (p PasswordChange) Validate() error {
+	v := &validator{}
+	if v.required("password", p.Password) {
+		v.password("password", p.Password)
+	}
+	return v.err()
+}
+
+func (r RoleChange) Validate() error {
+	v := &validator{}
+	if v.required("role", r.Role) {
+		switch r.Role {
+		case RoleAdmin, RoleMember, RoleBanned:
+		default:
+			v.add("role", InvalidValue, "role must be admin, member or banned")
+		}
+	}
+	return v.err()
+}
+
+func (t Thread) Validate() error {
+	v := &validator{}
+	if v.required("author", t.Author) {
+		v.nickname("author", t.Author)
+	}
+	if v.required("title", t.Title) {
+		v.maxLength("title", t.Title, MaxTitleLength)
+	}
+	if v.required("message", t.Message) {
+		v.maxLength("message", t.Message, MaxMessageLength)
+	}
+	v.slug("slug", t.Slug)
+	return v.err()
+}
+
+/* omitted title or message keep their value */
+func (t ThreadUpdate) Validate() error {
+	v := &validator{}
+	v.maxLength("title", t.Title, MaxTitleLength)
+	v.maxLength("message", t.Message, MaxMessageLength)
+	return v.err()
+}
+
+func (vote Vote) Validate() error {
+	v := &validator{}
+	v.nickname("nickname", vote.User)
+	if vote.Voice != -1 && vote.Voice != 1 {
+		v.add("voice", InvalidValue, "voice must be -1 or 1, DELETE the vote to retract it")
+	}
+	return v.err()
+}
+
+func (p PostCreate) validate(v *validator) {
+	if v.required("author", p.Author) {
+		v.nickname("author", p.Author)
+	}
+	if v.required("message", p.Message) {
+		v.maxLength("message", p.Message, MaxMessageLength)
+	}
+	if p.Parent < 0 {
+		v.add("parent", InvalidValue, "parent must not be negative")
+	}
+}
+
+/* field names carry the index of the post in the batch, e.g. [3].author */
+func ValidatePosts(posts []PostCreate) error {
+	v := &validator{}
+	for i, post := range posts {
+		v.prefix = fmt.Sprintf("[%d].", i)
+		post.validate(v)
+	}
+	return v.err()
+}
+
+/* an omitted message keeps the post unchanged */
+func (p PostUpdate) Validate() error {
+	v := &validator{}
+	v.maxLength("message", p.Message, MaxMessageLength)
+	return v.err()
+}
@@ -0,0 +1,121 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+/* field:code of every rejected field in the order Validate reported them, nil for a valid input */
+func fieldCodes(t *testing.T, err error) []string {
+	t.Helper()
+
+	if err == nil {
+		return nil
+	}
+	var e Error
+	if !errors.As(err, &e) || !errors.Is(err, ErrValidation) {
+		t.Fatalf("got %v, want a %v", err, ErrValidation)
+	}
+	codes := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		codes = append(codes, field.Field+":"+field.Code)
+	}
+	return codes
+}
+
+func TestValidate(t *testing.T) {
+	long := func(n int) string { return strings.Repeat("a", n) }
+
+	tests := []struct {
+		name  string
+		input interface{ Validate() error }
+		want  []string
+	}{
+		{"forum", ForumCreate{Slug: "go-lang_1", Title: "t", User: "alice.b"}, nil},
+		{"forum empty", ForumCreate{}, []string{"slug:required", "title:required", "user:required"}},
+		{"forum blank title", ForumCreate{Slug: "f", Title: "  ", User: "alice"}, []string{"title:required"}},
+		{"forum digits only slug", ForumCreate{Slug: "123", Title: "t", User: "alice"}, []string{"slug:format"}},
+		{"forum slug with space", ForumCreate{Slug: "a b", Title: "t", User: "alice"}, []string{"slug:format"}},
+		{"forum long slug and title", ForumCreate{Slug: long(MaxSlugLength + 1), Title: long(MaxTitleLength + 1), User: "alice"}, []string{"slug:length", "title:length"}},
+		{"forum bad user", ForumCreate{Slug: "f", Title: "t", User: "al-ice"}, []string{"user:format"}},
+
+		{"user", User{Nickname: "alice_1.b", Fullname: "Alice", Email: "alice@test"}, nil},
+		{"user empty", User{}, []string{"nickname:required", "fullname:required", "email:required"}},
+		{"user bad fields", User{Nickname: "al ice", Fullname: "Alice", Email: "alice", About: long(MaxAboutLength + 1)}, []string{"nickname:format", "email:format", "about:length"}},
+		{"user long nickname", User{Nickname: long(MaxNicknameLength + 1), Fullname: "Alice", Email: "alice@test"}, []string{"nickname:length"}},
+		{"user nickname length in characters", User{Nickname: long(MaxNicknameLength), Fullname: strings.Repeat("é", MaxFullnameLength), Email: "alice@test"}, nil},
+
+		{"credentials", Credentials{Nickname: "alice", Password: long(MaxPasswordLength)}, nil},
+		{"credentials empty", Credentials{}, []string{"nickname:required", "password:required"}},
+		{"credentials long password", Credentials{Nickname: "alice", Password: long(MaxPasswordLength + 1)}, []string{"password:length"}},
+		{"credentials password length in bytes", Credentials{Nickname: "alice", Password: strings.Repeat("é", MaxPasswordLength/2+1)}, []string{"password:length"}},
+
+		{"password change", PasswordChange{Password: "secret"}, nil},
+		{"password change empty", PasswordChange{}, []string{"password:required"}},
+
+		{"role", RoleChange{Role: RoleBanned}, nil},
+		{"role empty", RoleChange{}, []string{"role:required"}},
+		{"role moderator", RoleChange{Role: RoleModerator}, []string{"role:value"}},
+
+		{"thread", Thread{Author: "alice", Title: "t", Message: "m"}, nil},
+		{"thread empty", Thread{}, []string{"author:required", "title:required", "message:required"}},
+		{"thread numeric slug", Thread{Author: "alice", Title: "t", Message: "m", Slug: "42"}, []string{"slug:format"}},
+		{"thread long message", Thread{Author: "alice", Title: "t", Message: long(MaxMessageLength + 1)}, []string{"message:length"}},
+
+		{"thread update empty", ThreadUpdate{}, nil},
+		{"thread update long title", ThreadUpdate{Title: long(MaxTitleLength + 1)}, []string{"title:length"}},
+
+		{"vote", Vote{Voice: -1}, nil},
+		{"vote without voice", Vote{User: "alice"}, []string{"voice:value"}},
+		{"vote bad nickname and voice", Vote{User: "al ice", Voice: 2}, []string{"nickname:format", "voice:value"}},
+
+		{"post update empty", PostUpdate{}, nil},
+		{"post update long message", PostUpdate{Message: long(MaxMessageLength + 1)}, []string{"message:length"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fieldCodes(t, tt.input.Validate()); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateUserUpdate(t *testing.T) {
+	if got := fieldCodes(t, User{}.ValidateUpdate()); got != nil {
+		t.Errorf("empty update: got %v, want nil", got)
+	}
+	want := []string{"fullname:length", "email:format"}
+	if got := fieldCodes(t, User{Fullname: strings.Repeat("a", MaxFullnameLength+1), Email: "alice"}.ValidateUpdate()); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+/* field names carry the index of the post, so one response points at every bad post of the batch */
+func TestValidatePosts(t *testing.T) {
+	posts := []PostCreate{
+		{Author: "alice", Message: "m"},
+		{Parent: -1},
+		{Author: "bob", Message: "m", Parent: 1},
+		{Author: "b ob", Message: "m"},
+	}
+	want := []string{"[1].author:required", "[1].message:required", "[1].parent:value", "[3].author:format"}
+	if got := fieldCodes(t, ValidatePosts(posts)); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if got := fieldCodes(t, ValidatePosts(posts[:1])); got != nil {
+		t.Errorf("valid batch: got %v, want nil", got)
+	}
+}
+
+func TestMalformedBody(t *testing.T) {
+	err := MalformedBody(errors.New("unexpected EOF"))
+	var e Error
+	if !errors.As(err, &e) || !errors.Is(err, ErrValidation) || e.Code != "malformed_body" {
+		t.Errorf("got %#v, want a %v with code malformed_body", err, ErrValidation)
+	}
+}
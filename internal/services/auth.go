@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/pringleskate/tp_db_forum/internal/models"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"time"
+)
+
+/* bcrypt of a password nobody has at bcrypt.DefaultCost, compared against when there is no stored hash */
+var dummyHash = []byte("$2a$10$b3s46zTgRAW1U93BLM8qWOTtkhXEADXHmtqqqbhvzWO/.AbUPJYLe")
+
+func (s service) Login(ctx context.Context, input models.Credentials) (models.Session, error) {
+	nickname, hash, err := s.authStorage.GetPassword(ctx, input.Nickname)
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
+		return models.Session{}, err
+	}
+
+	/*
+		unknown user, no password set and wrong password look the same to the caller,
+		in time as well: bcrypt runs on every path so the response time does not tell which users exist
+	*/
+	known := err == nil && hash != nil
+	if !known {
+		hash = dummyHash
+	}
+	if bcrypt.CompareHashAndPassword(hash, []byte(input.Password)) != nil || !known {
+		return models.Session{}, models.ErrUnauthorized.WithCode("invalid_credentials").WithMessage("invalid nickname or password")
+	}
+
+	token := make([]byte, 32)
+	if _, err = rand.Read(token); err != nil {
+		return models.Session{}, models.ErrInternal.Wrap(err)
+	}
+
+	session := models.Session{
+		Nickname: nickname,
+		Token:    hex.EncodeToString(token),
+		Expires:  time.Now().Add(s.sessionTTL),
+	}
+	if err = s.authStorage.CreateSession(ctx, nickname, hashToken(session.Token), session.Expires); err != nil {
+		return models.Session{}, err
+	}
+
+	return session, nil
+}
+
+func (s service) Logout(ctx context.Context, token string) error {
+	return s.authStorage.DeleteSession(ctx, hashToken(token))
+}
+
+/* returns the nickname the token was issued to */
+func (s service) Authenticate(ctx context.Context, token string) (string, error) {
+	if token == "" {
+		return "", models.ErrUnauthorized.WithCode("missing_token").WithMessage("missing token")
+	}
+	return s.authStorage.GetSession(ctx, hashToken(token))
+}
+
+func (s service) ChangePassword(ctx context.Context, nickname string, password string, editor string) error {
+	if !strings.EqualFold(nickname, editor) {
+		if err := s.checkAdmin(ctx, editor); err != nil {
+			return err
+		}
+	}
+
+	hash, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	return s.authStorage.SetPassword(ctx, nickname, hash)
+}
+
+func hashPassword(password string) ([]byte, error) {
+	if password == "" || len(password) > models.MaxPasswordLength {
+		return nil, models.ErrValidation.WithMessage("password must be 1 to 72 bytes long")
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, models.ErrInternal.Wrap(err)
+	}
+
+	return hash, nil
+}
+
+func hashToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/pringleskate/tp_db_forum/internal/models"
+	"strings"
+	"sync"
+	"time"
+)
+
+/* how long a confirmation token handed out by an unconfirmed clear stays usable */
+const clearConfirmTTL = time.Minute
+
+/*
+Outside test mode clearing is off unless the config allows it, and then takes two calls by an admin:
+the first one returns a confirmation token, the second one has to present it for the same scope.
+Test mode keeps the old single unauthenticated call the functional test suite relies on.
+*/
+func (s service) Clear(ctx context.Context, input models.ClearInput, actor string) (models.ClearConfirmation, error) {
+	if !s.testMode {
+		if !s.allowClear {
+			return models.ClearConfirmation{}, models.ErrForbidden.WithCode("clear_disabled").WithMessage("clear is disabled")
+		}
+		if actor == "" {
+			return models.ClearConfirmation{}, models.ErrUnauthorized.WithCode("missing_token").WithMessage("missing token")
+		}
+		if err := s.checkAdmin(ctx, actor); err != nil {
+			return models.ClearConfirmation{}, err
+		}
+
+		if input.Confirm == "" {
+			return s.clearTokens.issue(actor, input.Forum)
+		}
+		if !s.clearTokens.redeem(input.Confirm, actor, input.Forum) {
+			return models.ClearConfirmation{}, models.ErrConflict.WithCode("invalid_confirmation").WithMessage("invalid or expired confirmation token")
+		}
+	}
+
+	if input.Forum != "" {
+		return models.ClearConfirmation{}, s.databaseService.ClearForum(ctx, input.Forum)
+	}
+	return models.ClearConfirmation{}, s.databaseService.Clear(ctx)
+}
+
+/* single use tokens, each bound to the admin that asked for it and to the scope */
+type confirmations struct {
+	mu      sync.Mutex
+	pending map[string]confirmation
+}
+
+type confirmation struct {
+	actor   string
+	forum   string
+	expires time.Time
+}
+
+func newConfirmations() *confirmations {
+	return &confirmations{
+		pending: make(map[string]confirmation),
+	}
+}
+
+func (c *confirmations) issue(actor string, forum string) (models.ClearConfirmation, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return models.ClearConfirmation{}, models.ErrInternal.Wrap(err)
+	}
+
+	result := models.ClearConfirmation{
+		Confirm: hex.EncodeToString(token),
+		Forum:   forum,
+		Expires: time.Now().Add(clearConfirmTTL),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for token, pending := range c.pending {
+		if now.After(pending.expires) {
+			delete(c.pending, token)
+		}
+	}
+	c.pending[result.Confirm] = confirmation{actor: actor, forum: forum, expires: result.Expires}
+
+	return result, nil
+}
+
+func (c *confirmations) redeem(token string, actor string, forum string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pending, ok := c.pending[token]
+	if !ok {
+		return false
+	}
+	delete(c.pending, token)
+
+	return time.Now().Before(pending.expires) &&
+		strings.EqualFold(pending.actor, actor) &&
+		strings.EqualFold(pending.forum, forum)
+}
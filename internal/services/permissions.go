@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"github.com/pringleskate/tp_db_forum/internal/models"
+	"strings"
+)
+
+/*
+	Every mutating call asks one of these first.
+	Admins may do anything, forum moderators may edit, archive and delete content of their forums,
+	authors may edit and delete their own posts and threads and archive their threads,
+	banned users may not change anything.
+*/
+
+func (s service) role(ctx context.Context, actor string) (string, error) {
+	for _, admin := range s.admins {
+		if strings.EqualFold(admin, actor) {
+			return models.RoleAdmin, nil
+		}
+	}
+	return s.roleStorage.GetRole(ctx, actor)
+}
+
+/* returns the actor's role so callers do not have to look it up again */
+func (s service) checkActive(ctx context.Context, actor string) (string, error) {
+	role, err := s.role(ctx, actor)
+	if err != nil {
+		return role, err
+	}
+	if role == models.RoleBanned {
+		return role, models.ErrForbidden.WithCode("user_banned").WithMessage("user is banned")
+	}
+	return role, nil
+}
+
+func (s service) checkAdmin(ctx context.Context, actor string) error {
+	role, err := s.checkActive(ctx, actor)
+	if err != nil {
+		return err
+	}
+	if role != models.RoleAdmin {
+		return models.ErrForbidden.WithCode("admin_required").WithMessage("only administrators can do this")
+	}
+	return nil
+}
+
+/* owner may be empty when only moderators and admins are allowed */
+func (s service) checkModerator(ctx context.Context, actor string, owner string, forum string) error {
+	role, err := s.checkActive(ctx, actor)
+	if err != nil {
+		return err
+	}
+	if role == models.RoleAdmin || owner != "" && strings.EqualFold(actor, owner) {
+		return nil
+	}
+
+	ok, err := s.roleStorage.IsModerator(ctx, actor, forum)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		if owner != "" {
+			return models.ErrForbidden.WithMessage("only the author or a forum moderator can do this")
+		}
+		return models.ErrForbidden.WithMessage("only a forum moderator can do this")
+	}
+	return nil
+}
+
+/* nobody posts on behalf of another user, banned users do not post at all */
+func (s service) checkAuthor(ctx context.Context, actor string, authors ...string) error {
+	for _, author := range authors {
+		if !strings.EqualFold(author, actor) {
+			return models.ErrForbidden.WithMessage("cannot post on behalf of another user")
+		}
+	}
+	_, err := s.checkActive(ctx, actor)
+	return err
+}
+
+/* for calls that name their author in the body instead of authenticating */
+func (s service) checkNotBanned(ctx context.Context, nicknames ...string) error {
+	banned, err := s.roleStorage.GetBanned(ctx, nicknames)
+	if err != nil {
+		return err
+	}
+	if len(banned) != 0 {
+		return models.ErrForbidden.WithMessage("user " + banned[0] + " is banned")
+	}
+	return nil
+}
+
+func (s service) SetRole(ctx context.Context, nickname string, role string, actor string) error {
+	if err := s.checkAdmin(ctx, actor); err != nil {
+		return err
+	}
+
+	switch role {
+	case models.RoleAdmin, models.RoleMember, models.RoleBanned:
+	default:
+		return models.ErrValidation.WithMessage("role must be admin, member or banned")
+	}
+
+	return s.roleStorage.SetRole(ctx, nickname, role)
+}
+
+func (s service) GrantModerator(ctx context.Context, forum string, nickname string, actor string) ([]string, error) {
+	if err := s.checkAdmin(ctx, actor); err != nil {
+		return nil, err
+	}
+	if err := s.roleStorage.GrantModerator(ctx, nickname, forum, actor); err != nil {
+		return nil, err
+	}
+	return s.roleStorage.GetModerators(ctx, forum)
+}
+
+func (s service) RevokeModerator(ctx context.Context, forum string, nickname string, actor string) ([]string, error) {
+	if err := s.checkAdmin(ctx, actor); err != nil {
+		return nil, err
+	}
+	if err := s.roleStorage.RevokeModerator(ctx, nickname, forum); err != nil {
+		return nil, err
+	}
+	return s.roleStorage.GetModerators(ctx, forum)
+}
+
+func (s service) GetModerators(ctx context.Context, forum string) ([]string, error) {
+	return s.roleStorage.GetModerators(ctx, forum)
+}
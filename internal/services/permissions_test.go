@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/pringleskate/tp_db_forum/internal/models"
+	"github.com/pringleskate/tp_db_forum/internal/storages/memoryStorage"
+	"testing"
+)
+
+/* alice is an admin, bob moderates forum f, carol and dave are members */
+func newMemoryService(t *testing.T) Service {
+	t.Helper()
+
+	db := memoryStorage.NewDatabase()
+	s := NewService(memoryStorage.NewForumStorage(db), memoryStorage.NewThreadStorage(db), memoryStorage.NewUserStorage(db),
+		memoryStorage.NewPostStorage(db), memoryStorage.NewVoteStorage(db), memoryStorage.NewSearchStorage(db),
+		memoryStorage.NewAuthStorage(db), memoryStorage.NewRoleStorage(db), memoryStorage.NewDatabaseService(db),
+		Options{Admins: []string{"alice"}})
+
+	ctx := context.Background()
+	for _, nickname := range []string{"alice", "bob", "carol", "dave"} {
+		if _, err := s.CreateUser(ctx, models.User{Nickname: nickname, Email: nickname + "@test"}, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.CreateForum(ctx, models.ForumCreate{Slug: "f", Title: "f", User: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GrantModerator(ctx, "f", "bob", "alice"); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestThreadRemovalPermissions(t *testing.T) {
+	tests := []struct {
+		actor string
+		want  error
+	}{
+		{actor: "carol"},
+		{actor: "dave", want: models.ErrForbidden},
+		{actor: "bob"},
+		{actor: "alice"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.actor, func(t *testing.T) {
+			s := newMemoryService(t)
+			ctx := context.Background()
+
+			for _, remove := range []func(models.ThreadInput, string) (models.Thread, error){
+				func(input models.ThreadInput, actor string) (models.Thread, error) {
+					return s.ArchiveThread(ctx, input, actor)
+				},
+				func(input models.ThreadInput, actor string) (models.Thread, error) {
+					return s.DeleteThread(ctx, input, actor)
+				},
+			} {
+				thread, err := s.CreateThread(ctx, models.Thread{Author: "carol", Forum: "f", Title: "t", Message: "m"}, "carol")
+				if err != nil {
+					t.Fatal(err)
+				}
+				_, err = remove(models.ThreadInput{ThreadID: thread.ID}, tt.actor)
+				if tt.want == nil && err != nil || tt.want != nil && !errors.Is(err, tt.want) {
+					t.Errorf("got %v, want %v", err, tt.want)
+				}
+			}
+		})
+	}
+}
+
+/* threads and posts are written only by the authenticated author, a refused batch leaves no post behind */
+func TestCreateAsAnotherUser(t *testing.T) {
+	s := newMemoryService(t)
+	ctx := context.Background()
+
+	if _, err := s.CreateThread(ctx, models.Thread{Author: "carol", Forum: "f", Title: "t", Message: "m"}, "dave"); !errors.Is(err, models.ErrForbidden) {
+		t.Errorf("thread as carol by dave: got %v, want %v", err, models.ErrForbidden)
+	}
+	thread, err := s.CreateThread(ctx, models.Thread{Author: "carol", Forum: "f", Title: "t", Message: "m"}, "Carol")
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := models.ThreadInput{ThreadID: thread.ID}
+
+	_, err = s.CreatePosts(ctx, []models.PostCreate{{Author: "dave", Message: "m"}, {Author: "carol", Message: "m"}}, input, "dave")
+	if !errors.Is(err, models.ErrForbidden) {
+		t.Errorf("post as carol by dave: got %v, want %v", err, models.ErrForbidden)
+	}
+	if posts, _ := s.GetThreadPosts(ctx, models.ThreadGetPosts{ThreadInput: input, Limit: 10}); len(posts) != 0 {
+		t.Errorf("refused batch wrote %d posts", len(posts))
+	}
+
+	if _, err = s.CreatePosts(ctx, []models.PostCreate{{Author: "dave", Message: "m"}}, input, "dave"); err != nil {
+		t.Errorf("post as dave by dave: %v", err)
+	}
+
+	if err = s.SetRole(ctx, "dave", models.RoleBanned, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.CreatePosts(ctx, []models.PostCreate{{Author: "dave", Message: "m"}}, input, "dave"); !errors.Is(err, models.ErrForbidden) {
+		t.Errorf("post by banned dave: got %v, want %v", err, models.ErrForbidden)
+	}
+}
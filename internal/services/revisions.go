@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"github.com/pringleskate/tp_db_forum/internal/models"
+	"strings"
+)
+
+/*
+	Edit history of a post, visible to whoever may edit it: the author, moderators of its forum and admins.
+	A post nobody edited has a single revision, its current text.
+*/
+
+func (s service) GetPostRevisions(ctx context.Context, id int, actor string) ([]models.PostRevision, error) {
+	post := models.Post{}
+	if err := s.postStorage.GetPostDetails(ctx, models.PostInput{ID: id}, &post); err != nil {
+		return nil, err
+	}
+	if err := s.checkModerator(ctx, actor, post.Author, post.Forum); err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.postStorage.GetPostRevisions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(revisions) == 0 {
+		revisions = append(revisions, models.PostRevision{Revision: 1, Message: post.Message, Editor: post.Author, Created: post.Created})
+	}
+	return revisions, nil
+}
+
+/* to defaults to the latest revision, from to the one before it */
+func (s service) DiffPostRevisions(ctx context.Context, id int, from int, to int, actor string) (models.PostDiff, error) {
+	revisions, err := s.GetPostRevisions(ctx, id, actor)
+	if err != nil {
+		return models.PostDiff{}, err
+	}
+
+	if to == 0 {
+		to = len(revisions)
+	}
+	if from == 0 {
+		from = to - 1
+		if from < 1 {
+			from = 1
+		}
+	}
+	if from < 1 || from > len(revisions) || to < 1 || to > len(revisions) {
+		return models.PostDiff{}, models.ErrNotFound.WithMessage("cannot find revision")
+	}
+
+	diff := models.PostDiff{
+		Post: id,
+		From: revisions[from-1],
+		To:   revisions[to-1],
+	}
+	diff.Lines = diffLines(splitLines(diff.From.Message), splitLines(diff.To.Message))
+	return diff, nil
+}
+
+func splitLines(message string) []string {
+	if message == "" {
+		return nil
+	}
+	return strings.Split(message, "\n")
+}
+
+/* past this many inserted plus deleted lines the changed middle is shown as replaced wholesale */
+const maxDiffEdits = 1000
+
+/* common head and tail are cut off first, so typical small edits of long posts stay cheap */
+func diffLines(a, b []string) []models.DiffLine {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]models.DiffLine, 0, len(a)+len(b))
+	lines = appendDiff(lines, models.DiffEqual, a[:prefix])
+	lines = append(lines, myersDiff(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	lines = appendDiff(lines, models.DiffEqual, a[len(a)-suffix:])
+	return lines
+}
+
+func appendDiff(lines []models.DiffLine, op string, texts []string) []models.DiffLine {
+	for _, text := range texts {
+		lines = append(lines, models.DiffLine{Op: op, Text: text})
+	}
+	return lines
+}
+
+/* shortest edit script, Myers' greedy algorithm keeping the frontier of every step for the walk back */
+func myersDiff(a, b []string) []models.DiffLine {
+	n, m := len(a), len(b)
+	max := n + m
+	if max > maxDiffEdits {
+		max = maxDiffEdits
+	}
+
+	offset := max + 1
+	v := make([]int, 2*max+3)
+	trace := make([][]int, 0, max+1)
+
+	for d := 0; d <= max; d++ {
+		done := false
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || k != d && v[offset+k-1] < v[offset+k+1] {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				done = true
+				break
+			}
+		}
+
+		frontier := make([]int, 2*d+1)
+		copy(frontier, v[offset-d:offset+d+1])
+		trace = append(trace, frontier)
+
+		if done {
+			return walkBack(a, b, trace)
+		}
+	}
+
+	lines := appendDiff(nil, models.DiffDelete, a)
+	return appendDiff(lines, models.DiffInsert, b)
+}
+
+func walkBack(a, b []string, trace [][]int) []models.DiffLine {
+	at := func(d, k int) int { return trace[d][k+d] }
+
+	x, y := len(a), len(b)
+	reversed := make([]models.DiffLine, 0, len(a)+len(b))
+	for d := len(trace) - 1; d > 0; d-- {
+		k := x - y
+		prevK := k - 1
+		if k == -d || k != d && at(d-1, k-1) < at(d-1, k+1) {
+			prevK = k + 1
+		}
+		prevX := at(d-1, prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			reversed = append(reversed, models.DiffLine{Op: models.DiffEqual, Text: a[x-1]})
+			x, y = x-1, y-1
+		}
+		if prevK == k+1 {
+			reversed = append(reversed, models.DiffLine{Op: models.DiffInsert, Text: b[prevY]})
+		} else {
+			reversed = append(reversed, models.DiffLine{Op: models.DiffDelete, Text: a[prevX]})
+		}
+		x, y = prevX, prevY
+	}
+	for x > 0 {
+		reversed = append(reversed, models.DiffLine{Op: models.DiffEqual, Text: a[x-1]})
+		x--
+	}
+
+	lines := make([]models.DiffLine, len(reversed))
+	for i, line := range reversed {
+		lines[len(reversed)-1-i] = line
+	}
+	return lines
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/pringleskate/tp_db_forum/internal/models"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+/* op and text of every line as "=a", "-a" or "+a" */
+func diffOps(lines []models.DiffLine) []string {
+	symbols := map[string]string{models.DiffEqual: "=", models.DiffDelete: "-", models.DiffInsert: "+"}
+	ops := make([]string, 0, len(lines))
+	for _, line := range lines {
+		ops = append(ops, symbols[line.Op]+line.Text)
+	}
+	return ops
+}
+
+func numbered(prefix string, n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("%s%d", prefix, i)
+	}
+	return lines
+}
+
+func TestDiffLines(t *testing.T) {
+	/* more than maxDiffEdits edits, with an equal line Myers would have kept in the middle */
+	long := func(prefix string) []string {
+		lines := append(numbered(prefix, maxDiffEdits/2+1), "common")
+		return append(append([]string{"head"}, lines...), append(numbered(prefix+"_", maxDiffEdits/2+1), "tail")...)
+	}
+	fallback := []string{"=head"}
+	for _, line := range long("a")[1 : len(long("a"))-1] {
+		fallback = append(fallback, "-"+line)
+	}
+	for _, line := range long("b")[1 : len(long("b"))-1] {
+		fallback = append(fallback, "+"+line)
+	}
+	fallback = append(fallback, "=tail")
+
+	tests := []struct {
+		name  string
+		a, b  []string
+		want  []string
+		edits int
+	}{
+		{name: "both empty", want: []string{}},
+		{name: "empty to text", b: []string{"a", "b"}, want: []string{"+a", "+b"}, edits: 2},
+		{name: "text to empty", a: []string{"a", "b"}, want: []string{"-a", "-b"}, edits: 2},
+		{name: "unchanged", a: []string{"a", "b"}, b: []string{"a", "b"}, want: []string{"=a", "=b"}},
+		{name: "change in the middle", a: []string{"a", "b", "c"}, b: []string{"a", "x", "c"}, want: []string{"=a", "-b", "+x", "=c"}, edits: 2},
+		{
+			name:  "shared prefix and suffix",
+			a:     []string{"a", "b", "c", "d"},
+			b:     []string{"a", "b", "x", "y", "c", "d"},
+			want:  []string{"=a", "=b", "+x", "+y", "=c", "=d"},
+			edits: 2,
+		},
+		{name: "edits on both ends", a: []string{"a", "b", "c"}, b: []string{"b", "c", "d"}, want: []string{"-a", "=b", "=c", "+d"}, edits: 2},
+		/* the example of Myers' paper, ABCABBA to CBABAC takes 5 edits */
+		{name: "interleaved", a: strings.Split("ABCABBA", ""), b: strings.Split("CBABAC", ""), edits: 5},
+		{name: "above maxDiffEdits", a: long("a"), b: long("b"), want: fallback, edits: 2*len(long("a")) - 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := diffLines(tt.a, tt.b)
+			got := diffOps(lines)
+			if tt.want != nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+
+			var from, to []string
+			edits := 0
+			for _, line := range lines {
+				if line.Op != models.DiffInsert {
+					from = append(from, line.Text)
+				}
+				if line.Op != models.DiffDelete {
+					to = append(to, line.Text)
+				}
+				if line.Op != models.DiffEqual {
+					edits++
+				}
+			}
+			if strings.Join(from, "\n") != strings.Join(tt.a, "\n") || strings.Join(to, "\n") != strings.Join(tt.b, "\n") {
+				t.Errorf("%v does not rebuild both sides", got)
+			}
+			if edits != tt.edits {
+				t.Errorf("%d edits, want %d", edits, tt.edits)
+			}
+		})
+	}
+}
+
+/* carol wrote the post, bob moderates its forum, alice is an admin, dave is nobody to it */
+func TestPostRevisionsPermissions(t *testing.T) {
+	s := newMemoryService(t)
+	ctx := context.Background()
+
+	thread, err := s.CreateThread(ctx, models.Thread{Author: "carol", Forum: "f", Title: "t", Message: "m"}, "carol")
+	if err != nil {
+		t.Fatal(err)
+	}
+	posts, err := s.CreatePosts(ctx, []models.PostCreate{{Author: "carol", Message: "first"}}, models.ThreadInput{ThreadID: thread.ID}, "carol")
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := posts[0].ID
+	if _, err = s.UpdatePost(ctx, models.PostUpdate{ID: id, Message: "second"}, "carol"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		actor string
+		want  error
+	}{
+		{actor: "dave", want: models.ErrForbidden},
+		{actor: "carol"},
+		{actor: "bob"},
+		{actor: "alice"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.actor, func(t *testing.T) {
+			revisions, err := s.GetPostRevisions(ctx, id, tt.actor)
+			if tt.want != nil {
+				if !errors.Is(err, tt.want) {
+					t.Errorf("got %v, want %v", err, tt.want)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(revisions) != 2 || revisions[0].Message != "first" || revisions[1].Message != "second" {
+				t.Errorf("got %+v, want revisions first and second", revisions)
+			}
+		})
+	}
+}
@@ -1,40 +1,70 @@
 package services
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"github.com/pringleskate/tp_db_forum/internal/models"
+	"github.com/pringleskate/tp_db_forum/internal/storages/authStorage"
 	"github.com/pringleskate/tp_db_forum/internal/storages/databaseService"
 	"github.com/pringleskate/tp_db_forum/internal/storages/forumStorage"
 	"github.com/pringleskate/tp_db_forum/internal/storages/postStorage"
+	"github.com/pringleskate/tp_db_forum/internal/storages/roleStorage"
+	"github.com/pringleskate/tp_db_forum/internal/storages/searchStorage"
 	"github.com/pringleskate/tp_db_forum/internal/storages/threadStorage"
 	"github.com/pringleskate/tp_db_forum/internal/storages/userStorage"
 	"github.com/pringleskate/tp_db_forum/internal/storages/voteStorage"
 	"math"
 	"strings"
+	"time"
 )
 
 type Service interface {
-	CreateForum(input models.ForumCreate) (models.Forum, error)
-	GetForum(input models.ForumInput) (models.Forum, error)
-	GetForumThreads(input models.ForumGetThreads) ([]models.Thread, error)
-	GetForumUsers(input models.ForumGetUsers) ([]models.User, error)
-
-	CreateUser(input models.User) ([]models.User, error)
-	GetUser(nickname string) (models.User, error)
-	UpdateUser(input models.User) (models.User, error)
-
-	CreateThread(input models.Thread) (models.Thread, error)
-	ThreadVote(input models.Vote) (models.Thread, error)
-	GetThread(input models.ThreadInput) (models.Thread, error)
-	UpdateThread(input models.ThreadUpdate) (models.Thread, error)
-	GetThreadPosts(input models.ThreadGetPosts) ([]models.Post, error)
-
-//	CreatePosts(input []models.PostCreate, thread models.ThreadInput) ([]models.Post, error)
-	GetPost(id int, related string) (models.PostFull, error)
-	UpdatePost(input models.PostUpdate) (models.Post, error)
-
-	Clear()
-	Status() models.Status
+	CreateForum(ctx context.Context, input models.ForumCreate) (models.Forum, error)
+	GetForum(ctx context.Context, input models.ForumInput) (models.Forum, error)
+	GetForumThreads(ctx context.Context, input models.ForumGetThreads) ([]models.Thread, error)
+	GetForumUsers(ctx context.Context, input models.ForumGetUsers) ([]models.User, error)
+	GetForums(ctx context.Context, input models.ForumList) ([]models.Forum, error)
+
+	CreateUser(ctx context.Context, input models.User, password string) ([]models.User, error)
+	GetUser(ctx context.Context, nickname string) (models.User, error)
+	UpdateUser(ctx context.Context, input models.User, editor string) (models.User, error)
+
+	CreateThread(ctx context.Context, input models.Thread, actor string) (models.Thread, error)
+	ThreadVote(ctx context.Context, input models.Vote, voter string) (models.Thread, error)
+	ThreadUnvote(ctx context.Context, input models.Vote, voter string) (models.Thread, error)
+	GetThreadVotes(ctx context.Context, input models.ThreadGetVotes) ([]models.VoteRecord, error)
+	GetUserVotes(ctx context.Context, input models.UserGetVotes) ([]models.VoteRecord, error)
+	GetThread(ctx context.Context, input models.ThreadInput) (models.Thread, error)
+	UpdateThread(ctx context.Context, input models.ThreadUpdate, editor string) (models.Thread, error)
+	GetThreadPosts(ctx context.Context, input models.ThreadGetPosts) ([]models.Post, error)
+	DeleteThread(ctx context.Context, input models.ThreadInput, actor string) (models.Thread, error)
+	ArchiveThread(ctx context.Context, input models.ThreadInput, actor string) (models.Thread, error)
+
+	CreatePosts(ctx context.Context, input []models.PostCreate, thread models.ThreadInput, actor string) ([]models.Post, error)
+	GetPost(ctx context.Context, id int, related string) (models.PostFull, error)
+	UpdatePost(ctx context.Context, input models.PostUpdate, editor string) (models.Post, error)
+	DeletePost(ctx context.Context, id int, actor string) (models.Post, error)
+	PostVote(ctx context.Context, input models.Vote, voter string) (models.Post, error)
+	PostUnvote(ctx context.Context, input models.Vote, voter string) (models.Post, error)
+	GetPostRevisions(ctx context.Context, id int, actor string) ([]models.PostRevision, error)
+	DiffPostRevisions(ctx context.Context, id int, from int, to int, actor string) (models.PostDiff, error)
+
+	Search(ctx context.Context, input models.SearchInput) ([]models.SearchResult, error)
+
+	Login(ctx context.Context, input models.Credentials) (models.Session, error)
+	Logout(ctx context.Context, token string) error
+	Authenticate(ctx context.Context, token string) (string, error)
+	ChangePassword(ctx context.Context, nickname string, password string, editor string) error
+
+	SetRole(ctx context.Context, nickname string, role string, actor string) error
+	GrantModerator(ctx context.Context, forum string, nickname string, actor string) ([]string, error)
+	RevokeModerator(ctx context.Context, forum string, nickname string, actor string) ([]string, error)
+	GetModerators(ctx context.Context, forum string) ([]string, error)
+
+	Clear(ctx context.Context, input models.ClearInput, actor string) (models.ClearConfirmation, error)
+	Status(ctx context.Context) (models.Status, error)
+	Ready(ctx context.Context) models.Health
+	ReconcileCounters(ctx context.Context, fix bool, actor string) (models.Reconciliation, error)
 }
 
 type service struct {
@@ -43,29 +73,57 @@ type service struct {
 	userStorage userStorage.Storage
 	postStorage postStorage.Storage
 	voteStorage voteStorage.Storage
+	searchStorage searchStorage.Storage
+	authStorage authStorage.Storage
+	roleStorage roleStorage.Storage
 	databaseService databaseService.Service
+	sessionTTL time.Duration
+	admins []string
+	testMode bool
+	allowClear bool
+	clearTokens *confirmations
 }
 
-func NewService(forumStorage forumStorage.Storage, threadStorage threadStorage.Storage, userStorage userStorage.Storage, postStorage postStorage.Storage, voteStorage voteStorage.Storage, databaseService databaseService.Service) Service {
+/* settings that come from the config rather than from storage */
+type Options struct {
+	SessionTTL time.Duration
+	Admins     []string
+	TestMode   bool
+	AllowClear bool
+}
+
+func NewService(forumStorage forumStorage.Storage, threadStorage threadStorage.Storage, userStorage userStorage.Storage, postStorage postStorage.Storage, voteStorage voteStorage.Storage, searchStorage searchStorage.Storage, authStorage authStorage.Storage, roleStorage roleStorage.Storage, databaseService databaseService.Service, options Options) Service {
 	return &service{
 		forumStorage:  forumStorage,
 		threadStorage: threadStorage,
 		userStorage:   userStorage,
 		postStorage:   postStorage,
 		voteStorage:   voteStorage,
+		searchStorage: searchStorage,
+		authStorage:   authStorage,
+		roleStorage:   roleStorage,
 		databaseService: databaseService,
+		sessionTTL:    options.SessionTTL,
+		admins:        options.Admins,
+		testMode:      options.TestMode,
+		allowClear:    options.AllowClear,
+		clearTokens:   newConfirmations(),
 	}
 }
 
-func (s service) CreateForum(input models.ForumCreate) (models.Forum, error) {
-	forum, err := s.forumStorage.CreateForum(input)
-	if err != nil && err.Error() == "409" {
-		oldForum, err := s.forumStorage.GetDetails(models.ForumInput{Slug: input.Slug})
+func (s service) CreateForum(ctx context.Context, input models.ForumCreate) (models.Forum, error) {
+	if err := s.checkNotBanned(ctx, input.User); err != nil {
+		return models.Forum{}, err
+	}
+
+	forum, err := s.forumStorage.CreateForum(ctx, input)
+	if err != nil && errors.Is(err, models.ErrConflict) {
+		oldForum, err := s.forumStorage.GetDetails(ctx, models.ForumInput{Slug: input.Slug})
 		if err != nil {
 			return models.Forum{}, err
 		}
 
-		return oldForum, models.Error{Code: "409", Message: "conflict slug"}
+		return oldForum, models.ErrConflict.WithMessage("conflict slug")
 	}
 
 	if err != nil {
@@ -75,23 +133,23 @@ func (s service) CreateForum(input models.ForumCreate) (models.Forum, error) {
 	return forum, nil
 }
 
-func (s service) GetForum(input models.ForumInput) (models.Forum, error) {
-	return s.forumStorage.GetDetails(input)
+func (s service) GetForum(ctx context.Context, input models.ForumInput) (models.Forum, error) {
+	return s.forumStorage.GetDetails(ctx, input)
 }
 
-func (s service) GetForumThreads(input models.ForumGetThreads) ([]models.Thread, error) {
-	err := s.forumStorage.CheckIfForumExists(models.ForumInput{Slug: input.Slug})
+func (s service) GetForumThreads(ctx context.Context, input models.ForumGetThreads) ([]models.Thread, error) {
+	err := s.forumStorage.CheckIfForumExists(ctx, models.ForumInput{Slug: input.Slug})
 	if err != nil {
 		return []models.Thread{}, err
 	}
 	if input.Limit == 0 {
 		input.Limit = math.MaxInt32
 	}
-	return s.threadStorage.GetThreadsByForum(input)
+	return s.threadStorage.GetThreadsByForum(ctx, input)
 }
 
-func (s service) GetForumUsers(input models.ForumGetUsers) ([]models.User, error) {
-	forumID, err := s.forumStorage.GetForumID(models.ForumInput{Slug: input.Slug})
+func (s service) GetForumUsers(ctx context.Context, input models.ForumGetUsers) ([]models.User, error) {
+	forumID, err := s.forumStorage.GetForumID(ctx, models.ForumInput{Slug: input.Slug})
 	if err != nil {
 		return []models.User{}, err
 	}
@@ -99,20 +157,35 @@ func (s service) GetForumUsers(input models.ForumGetUsers) ([]models.User, error
 	if input.Limit == 0 {
 		input.Limit = math.MaxInt32
 	}
-	return s.userStorage.GetUsers(input, forumID)
+	return s.userStorage.GetUsers(ctx, input, forumID)
 }
 
-func (s service) CreateUser(input models.User) ([]models.User, error) {
-	user, err := s.userStorage.CreateUser(input)
+func (s service) GetForums(ctx context.Context, input models.ForumList) ([]models.Forum, error) {
+	if input.Limit == 0 {
+		input.Limit = math.MaxInt32
+	}
+	return s.forumStorage.GetForums(ctx, input)
+}
+
+func (s service) CreateUser(ctx context.Context, input models.User, password string) ([]models.User, error) {
+	var hash []byte
+	if password != "" {
+		var err error
+		if hash, err = hashPassword(password); err != nil {
+			return []models.User{}, err
+		}
+	}
+
+	user, err := s.userStorage.CreateUser(ctx, input, hash)
 
 	if err == nil {
 		return []models.User{user}, err
 	}
 
 	users := make([]models.User, 0)
-	if err.Error() == "409" {
-		userNick, err := s.userStorage.GetProfile(input.Nickname)
-		if err != nil && err.Error() != "404"{
+	if errors.Is(err, models.ErrConflict) {
+		userNick, err := s.userStorage.GetProfile(ctx, input.Nickname)
+		if err != nil && !errors.Is(err, models.ErrNotFound){
 			return []models.User{}, err
 		}
 		if err == nil {
@@ -120,63 +193,56 @@ func (s service) CreateUser(input models.User) ([]models.User, error) {
 		}
 
 		if strings.ToLower(userNick.Email) == strings.ToLower(input.Email){
-			return users, models.Error{Code: "409", Message: "conflict"}
+			return users, models.ErrConflict.WithMessage("conflict")
 		}
 
-		userEmail, err := s.userStorage.GetEmailConflictUser(input.Email)
-		if err != nil && err.Error() != "404"{
+		userEmail, err := s.userStorage.GetEmailConflictUser(ctx, input.Email)
+		if err != nil && !errors.Is(err, models.ErrNotFound){
 			return []models.User{}, err
 		}
 		if err == nil {
 			users = append(users, userEmail)
 		}
 
-		return users, models.Error{Code: "409", Message: "conflict"}
+		return users, models.ErrConflict.WithMessage("conflict")
 	}
 
 	return []models.User{}, err
 }
 
-func (s service) GetUser(nickname string) (models.User, error) {
-	return s.userStorage.GetProfile(nickname)
+func (s service) GetUser(ctx context.Context, nickname string) (models.User, error) {
+	return s.userStorage.GetProfile(ctx, nickname)
 }
 
-func (s service) UpdateUser(input models.User) (models.User, error) {
+func (s service) UpdateUser(ctx context.Context, input models.User, editor string) (models.User, error) {
+	if !strings.EqualFold(input.Nickname, editor) {
+		if err := s.checkAdmin(ctx, editor); err != nil {
+			return models.User{}, err
+		}
+	} else if _, err := s.checkActive(ctx, editor); err != nil {
+		return models.User{}, err
+	}
+
 	if input.Email == "" && input.Fullname == "" && input.About == "" {
-		return s.userStorage.GetProfile(input.Nickname)
+		return s.userStorage.GetProfile(ctx, input.Nickname)
 	}
-	return s.userStorage.UpdateProfile(input)
+	return s.userStorage.UpdateProfile(ctx, input)
 }
 
-func (s service) CreateThread(input models.Thread) (models.Thread, error) {
-	thread, err := s.threadStorage.CreateThread(input)
-	if err == nil {
-		err = s.forumStorage.UpdateThreadsCount(models.ForumInput{Slug: input.Forum})
-		if err != nil {
-			return models.Thread{}, err
-		}
-		userID, err := s.userStorage.GetUserIDByNickname(input.Author)
-		if err != nil {
-			return models.Thread{}, err
-		}
-
-		forumID, err := s.forumStorage.GetForumID(models.ForumInput{Slug: input.Forum})
-		if err != nil {
-			return models.Thread{}, err
-		}
-
-		err = s.forumStorage.AddUserToForum(userID, forumID)
-		if err != nil && err.Error() != "409" {
-			return models.Thread{}, err
-		}
+func (s service) CreateThread(ctx context.Context, input models.Thread, actor string) (models.Thread, error) {
+	if err := s.checkAuthor(ctx, actor, input.Author); err != nil {
+		return models.Thread{}, err
+	}
 
+	thread, err := s.threadStorage.CreateThread(ctx, input)
+	if err == nil {
 		return thread, nil
 	}
 
-	if err.Error() == "409"  {
-		oldThread, err := s.threadStorage.GetDetails(models.ThreadInput{Slug: input.Slug})
+	if errors.Is(err, models.ErrConflict)  {
+		oldThread, err := s.threadStorage.GetDetails(ctx, models.ThreadInput{Slug: input.Slug})
 		if err == nil {
-			return oldThread, models.Error{Code: "409"}
+			return oldThread, models.ErrConflict
 		}
 		return thread, err
 	}
@@ -184,126 +250,169 @@ func (s service) CreateThread(input models.Thread) (models.Thread, error) {
 	return thread, err
 }
 
-func (s service) ThreadVote(input models.Vote) (models.Thread, error) {
-	thread, err := s.threadStorage.CheckThreadIfExists(input.Thread)
+func (s service) ThreadVote(ctx context.Context, input models.Vote, voter string) (models.Thread, error) {
+	if input.Voice != 1 && input.Voice != -1 {
+		return models.Thread{}, models.ErrValidation.WithMessage("voice must be -1 or 1")
+	}
+	input, err := s.threadVoter(ctx, input, voter)
 	if err != nil {
 		return models.Thread{}, err
 	}
-	input.Thread = thread
-
-	var updateFlag bool
+	return s.voteStorage.CreateVote(ctx, input)
+}
 
-	checkThread, err := s.voteStorage.CheckDoubleVote(input)
+/* retracting a vote never cast changes nothing */
+func (s service) ThreadUnvote(ctx context.Context, input models.Vote, voter string) (models.Thread, error) {
+	input, err := s.threadVoter(ctx, input, voter)
 	if err != nil {
-		if err.Error() == "409" {
-			return checkThread, nil
-		}
-		if err.Error() == "500" {
-			return models.Thread{}, err
-		}
-		if err.Error() == "101" {
-			updateFlag = true
-		}
+		return models.Thread{}, err
 	}
+	return s.voteStorage.DeleteVote(ctx, input)
+}
 
-	output, err := s.voteStorage.CreateVote(input, updateFlag)
+/* the vote as cast by voter on an existing thread */
+func (s service) threadVoter(ctx context.Context, input models.Vote, voter string) (models.Vote, error) {
+	input, err := s.voter(ctx, input, voter)
 	if err != nil {
-		return models.Thread{}, err
+		return input, err
 	}
 
-	return output, nil
+	input.Thread, err = s.threadStorage.CheckThreadIfExists(ctx, input.Thread)
+	return input, err
 }
 
-func (s service) GetThread(input models.ThreadInput) (models.Thread, error) {
-	return s.threadStorage.GetDetails(input)
-}
+/* nobody votes on behalf of another user, banned users do not vote at all */
+func (s service) voter(ctx context.Context, input models.Vote, voter string) (models.Vote, error) {
+	if input.User != "" && !strings.EqualFold(input.User, voter) {
+		return input, models.ErrForbidden.WithMessage("cannot vote on behalf of another user")
+	}
+	input.User = voter
 
-func (s service) UpdateThread(input models.ThreadUpdate) (models.Thread, error) {
-	return s.threadStorage.UpdateThread(input)
+	_, err := s.checkActive(ctx, voter)
+	return input, err
 }
 
-func (s service) GetThreadPosts(input models.ThreadGetPosts) ([]models.Post, error) {
-	thread, err := s.threadStorage.CheckThreadIfExists(input.ThreadInput)
+func (s service) GetThreadVotes(ctx context.Context, input models.ThreadGetVotes) ([]models.VoteRecord, error) {
+	thread, err := s.threadStorage.CheckThreadIfExists(ctx, input.ThreadInput)
 	if err != nil {
-		return []models.Post{}, err
+		return []models.VoteRecord{}, err
 	}
-
 	input.ThreadInput = thread
 
 	if input.Limit == 0 {
 		input.Limit = math.MaxInt32
 	}
-	return s.postStorage.GetPostsByThread(input)
+	return s.voteStorage.GetThreadVotes(ctx, input)
+}
+
+func (s service) GetUserVotes(ctx context.Context, input models.UserGetVotes) ([]models.VoteRecord, error) {
+	if _, err := s.userStorage.GetProfile(ctx, input.Nickname); err != nil {
+		return []models.VoteRecord{}, err
+	}
+
+	if input.Limit == 0 {
+		input.Limit = math.MaxInt32
+	}
+	return s.voteStorage.GetUserVotes(ctx, input)
 }
-/*
-func (s service) CreatePosts(input []models.PostCreate, thread models.ThreadInput) ([]models.Post, error) {
-	posts := make([]models.Post, 0)
 
-	forum, err := s.threadStorage.GetForumByThread(&thread)
+/* same rules as ThreadVote, input.Post names the post */
+func (s service) PostVote(ctx context.Context, input models.Vote, voter string) (models.Post, error) {
+	if input.Voice != 1 && input.Voice != -1 {
+		return models.Post{}, models.ErrValidation.WithMessage("voice must be -1 or 1")
+	}
+	input, err := s.voter(ctx, input, voter)
 	if err != nil {
-		return []models.Post{}, err
+		return models.Post{}, err
 	}
+	return s.voteStorage.CreatePostVote(ctx, input)
+}
 
-	if len(input) == 0 {
-		return []models.Post{}, nil
+func (s service) PostUnvote(ctx context.Context, input models.Vote, voter string) (models.Post, error) {
+	input, err := s.voter(ctx, input, voter)
+	if err != nil {
+		return models.Post{}, err
 	}
+	return s.voteStorage.DeletePostVote(ctx, input)
+}
 
-	createdTime := time.Now().Format(time.RFC3339Nano)
-	//created := time.Now()
-	for _, postInput := range input {
-		post := models.Post{
-			ThreadInput: thread,
-			Parent:      postInput.Parent,
-			Author:      postInput.Author,
-			Message:     postInput.Message,
-			Forum:       forum,
-			Created:     createdTime,
-		}
+func (s service) GetThread(ctx context.Context, input models.ThreadInput) (models.Thread, error) {
+	return s.threadStorage.GetDetails(ctx, input)
+}
 
-		if post.Parent != 0 {
-			parentThread, err := s.postStorage.CheckParentPostThread(post.Parent)
-			if err != nil {
-				fmt.Println(err)
-				return []models.Post{}, err
-			}
+func (s service) UpdateThread(ctx context.Context, input models.ThreadUpdate, editor string) (models.Thread, error) {
+	thread, err := s.threadStorage.GetDetails(ctx, input.ThreadInput)
+	if err != nil {
+		return models.Thread{}, err
+	}
+	if err = s.checkModerator(ctx, editor, thread.Author, thread.Forum); err != nil {
+		return models.Thread{}, err
+	}
 
-			if parentThread != post.ThreadID  {
-				return []models.Post{}, models.Error{Code:"409"}
-			}
-		}
+	return s.threadStorage.UpdateThread(ctx, input)
+}
 
-		output, err := s.postStorage.CreatePost(post)
-		if err != nil {
-			return []models.Post{}, err
-		}
+func (s service) GetThreadPosts(ctx context.Context, input models.ThreadGetPosts) ([]models.Post, error) {
+	thread, err := s.threadStorage.CheckThreadIfExists(ctx, input.ThreadInput)
+	if err != nil {
+		return []models.Post{}, err
+	}
 
-		posts = append(posts, output)
+	input.ThreadInput = thread
 
-		err = s.forumStorage.UpdatePostsCount(models.ForumInput{Slug: forum})
-		if err != nil {
-			return []models.Post{}, err
-		}
+	if input.Limit == 0 {
+		input.Limit = math.MaxInt32
 	}
+	return s.postStorage.GetPostsByThread(ctx, input)
+}
 
-	userID, err := s.userStorage.GetUserIDByNickname(input[0].Author)
+func (s service) DeleteThread(ctx context.Context, input models.ThreadInput, actor string) (models.Thread, error) {
+	thread, err := s.threadStorage.GetDetails(ctx, input)
 	if err != nil {
-		return []models.Post{}, err
+		return models.Thread{}, err
+	}
+	if err = s.checkModerator(ctx, actor, thread.Author, thread.Forum); err != nil {
+		return models.Thread{}, err
 	}
 
-	forumID, err := s.forumStorage.GetForumID(models.ForumInput{Slug: forum})
+	return s.threadStorage.DeleteThread(ctx, input)
+}
+
+func (s service) ArchiveThread(ctx context.Context, input models.ThreadInput, actor string) (models.Thread, error) {
+	thread, err := s.threadStorage.GetDetails(ctx, input)
+	if err != nil {
+		return models.Thread{}, err
+	}
+	if err = s.checkModerator(ctx, actor, thread.Author, thread.Forum); err != nil {
+		return models.Thread{}, err
+	}
+
+	return s.threadStorage.ArchiveThread(ctx, input)
+}
+
+func (s service) CreatePosts(ctx context.Context, input []models.PostCreate, thread models.ThreadInput, actor string) ([]models.Post, error) {
+	forum, err := s.threadStorage.GetForumByThread(ctx, &thread)
 	if err != nil {
 		return []models.Post{}, err
 	}
 
-	err = s.forumStorage.AddUserToForum(userID, forumID)
-	if err != nil && err.Error() != "409" {
+	if len(input) == 0 {
+		return []models.Post{}, nil
+	}
+
+	authors := make([]string, 0, len(input))
+	for _, post := range input {
+		authors = append(authors, post.Author)
+	}
+	if err = s.checkAuthor(ctx, actor, authors...); err != nil {
 		return []models.Post{}, err
 	}
 
-	return posts, nil
-}*/
+	created := time.Now().Truncate(time.Microsecond)
+	return s.postStorage.CreatePosts(ctx, thread, forum, created, input)
+}
 
-func (s service) GetPost(id int, related string) (models.PostFull, error) {
+func (s service) GetPost(ctx context.Context, id int, related string) (models.PostFull, error) {
 	postFull := models.PostFull{
 		Author: nil,
 		Forum:  nil,
@@ -311,7 +420,7 @@ func (s service) GetPost(id int, related string) (models.PostFull, error) {
 		Thread: nil,
 	}
 	post := new(models.Post)
-	err := s.postStorage.GetPostDetails(models.PostInput{ID: id}, post)
+	err := s.postStorage.GetPostDetails(ctx, models.PostInput{ID: id}, post)
 	postFull.Post = post
 	if err != nil {
 		return models.PostFull{}, err
@@ -319,7 +428,7 @@ func (s service) GetPost(id int, related string) (models.PostFull, error) {
 
 	author := new(models.User)
 	if strings.Contains(related, "user") {
-		err = s.userStorage.GetUserForPost(postFull.Post.Author, author)
+		err = s.userStorage.GetUserForPost(ctx, postFull.Post.Author, author)
 		postFull.Author = author
 		if err != nil {
 			return models.PostFull{}, err
@@ -328,7 +437,7 @@ func (s service) GetPost(id int, related string) (models.PostFull, error) {
 
 	forum := new(models.Forum)
 	if strings.Contains(related, "forum") {
-		err = s.forumStorage.GetForumForPost(postFull.Post.Forum, forum)
+		err = s.forumStorage.GetForumForPost(ctx, postFull.Post.Forum, forum)
 		postFull.Forum = forum
 		if err != nil {
 			return models.PostFull{}, err
@@ -337,7 +446,7 @@ func (s service) GetPost(id int, related string) (models.PostFull, error) {
 
 	thread := new(models.Thread)
 	if strings.Contains(related, "thread") {
-		err = s.threadStorage.GetThreadForPost(postFull.Post.ThreadInput, thread)
+		err = s.threadStorage.GetThreadForPost(ctx, postFull.Post.ThreadInput, thread)
 		postFull.Thread = thread
 		if err != nil {
 			return models.PostFull{}, err
@@ -347,21 +456,96 @@ func (s service) GetPost(id int, related string) (models.PostFull, error) {
 	return postFull, nil
 }
 
-func (s service) UpdatePost(input models.PostUpdate) (models.Post, error) {
-	return s.postStorage.UpdatePost(input)
+func (s service) UpdatePost(ctx context.Context, input models.PostUpdate, editor string) (models.Post, error) {
+	post := models.Post{}
+	if err := s.postStorage.GetPostDetails(ctx, models.PostInput{ID: input.ID}, &post); err != nil {
+		return models.Post{}, err
+	}
+	if err := s.checkModerator(ctx, editor, post.Author, post.Forum); err != nil {
+		return models.Post{}, err
+	}
+
+	return s.postStorage.UpdatePost(ctx, input, editor)
 }
 
-func (s service) Clear() {
-	err := s.databaseService.Clear()
-	if err != nil {
-		fmt.Println(err)
+func (s service) DeletePost(ctx context.Context, id int, actor string) (models.Post, error) {
+	post := models.Post{}
+	if err := s.postStorage.GetPostDetails(ctx, models.PostInput{ID: id}, &post); err != nil {
+		return models.Post{}, err
 	}
+	if err := s.checkModerator(ctx, actor, post.Author, post.Forum); err != nil {
+		return models.Post{}, err
+	}
+
+	return s.postStorage.DeletePost(ctx, models.PostInput{ID: id})
 }
 
-func (s service) Status() models.Status {
-	status, err := s.databaseService.Status()
+/* default page for search, ranking everything that matches is never what the caller wants */
+const searchLimit = 20
+
+func (s service) Search(ctx context.Context, input models.SearchInput) ([]models.SearchResult, error) {
+	if strings.TrimSpace(input.Query) == "" {
+		return []models.SearchResult{}, models.ErrValidation.WithMessage("query is required")
+	}
+	if input.Limit == 0 {
+		input.Limit = searchLimit
+	}
+
+	results, err := s.searchStorage.Search(ctx, input)
 	if err != nil {
-		fmt.Println(err)
+		return []models.SearchResult{}, err
+	}
+
+	for i := range results {
+		result := &results[i]
+		post := result.Post
+		if post == nil {
+			post = &models.Post{Author: result.Thread.Author, Forum: result.Thread.Forum}
+		}
+
+		if strings.Contains(input.Related, "user") {
+			result.Author = new(models.User)
+			if err = s.userStorage.GetUserForPost(ctx, post.Author, result.Author); err != nil {
+				return []models.SearchResult{}, err
+			}
+		}
+
+		if strings.Contains(input.Related, "forum") {
+			result.Forum = new(models.Forum)
+			if err = s.forumStorage.GetForumForPost(ctx, post.Forum, result.Forum); err != nil {
+				return []models.SearchResult{}, err
+			}
+		}
+
+		if strings.Contains(input.Related, "thread") && result.Thread == nil {
+			result.Thread = new(models.Thread)
+			if err = s.threadStorage.GetThreadForPost(ctx, post.ThreadInput, result.Thread); err != nil {
+				return []models.SearchResult{}, err
+			}
+		}
+	}
+
+	return results, nil
+}
+
+func (s service) Status(ctx context.Context) (models.Status, error) {
+	return s.databaseService.Status(ctx)
+}
+
+func (s service) Ready(ctx context.Context) models.Health {
+	health := models.Health{Status: models.HealthOK, Checks: s.databaseService.Ready(ctx)}
+	for _, check := range health.Checks {
+		if check.Status != models.HealthOK {
+			health.Status = models.HealthFail
+		}
+	}
+	return health
+}
+
+/* forums.threads, forums.posts and the vote scores recounted from the rows they summarize, fix writes them back */
+func (s service) ReconcileCounters(ctx context.Context, fix bool, actor string) (models.Reconciliation, error) {
+	if err := s.checkAdmin(ctx, actor); err != nil {
+		return models.Reconciliation{}, err
 	}
-	return status
+	return s.databaseService.ReconcileCounters(ctx, fix)
 }
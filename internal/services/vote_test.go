@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/pringleskate/tp_db_forum/internal/models"
+	"testing"
+)
+
+/* only the unvote calls retract, a vote without a voice is rejected and leaves the score alone */
+func TestVoteRetraction(t *testing.T) {
+	s := newMemoryService(t)
+	ctx := context.Background()
+
+	thread, err := s.CreateThread(ctx, models.Thread{Author: "carol", Forum: "f", Title: "t", Message: "m"}, "carol")
+	if err != nil {
+		t.Fatal(err)
+	}
+	posts, err := s.CreatePosts(ctx, []models.PostCreate{{Author: "carol", Message: "m"}}, models.ThreadInput{ThreadID: thread.ID}, "carol")
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := models.ThreadInput{ThreadID: thread.ID}
+	post := posts[0].ID
+
+	if _, err = s.ThreadVote(ctx, models.Vote{Voice: 1, Thread: input}, "dave"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.PostVote(ctx, models.Vote{Voice: 1, Post: post}, "dave"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = s.ThreadVote(ctx, models.Vote{Thread: input}, "dave"); !errors.Is(err, models.ErrValidation) {
+		t.Errorf("thread vote without voice: got %v, want %v", err, models.ErrValidation)
+	}
+	if _, err = s.PostVote(ctx, models.Vote{Post: post}, "dave"); !errors.Is(err, models.ErrValidation) {
+		t.Errorf("post vote without voice: got %v, want %v", err, models.ErrValidation)
+	}
+	if got, _ := s.GetThread(ctx, input); got.Votes != 1 {
+		t.Errorf("thread score after rejected vote: got %d, want 1", got.Votes)
+	}
+
+	if got, err := s.ThreadUnvote(ctx, models.Vote{Thread: input}, "dave"); err != nil || got.Votes != 0 {
+		t.Errorf("thread unvote: got %d, %v, want 0", got.Votes, err)
+	}
+	if got, err := s.PostUnvote(ctx, models.Vote{Post: post}, "dave"); err != nil || got.Votes != 0 {
+		t.Errorf("post unvote: got %d, %v, want 0", got.Votes, err)
+	}
+
+	if err = (models.Vote{User: "dave"}).Validate(); !errors.Is(err, models.ErrValidation) {
+		t.Errorf("Validate without voice: got %v, want %v", err, models.ErrValidation)
+	}
+}
@@ -0,0 +1,104 @@
+package authStorage
+
+import (
+	"context"
+	"github.com/jackc/pgx"
+	"github.com/pringleskate/tp_db_forum/internal/logger"
+	"github.com/pringleskate/tp_db_forum/internal/models"
+	"time"
+)
+
+type Storage interface {
+	SetPassword(ctx context.Context, nickname string, hash []byte) (err error)
+	GetPassword(ctx context.Context, nickname string) (user string, hash []byte, err error)
+	CreateSession(ctx context.Context, nickname string, tokenHash []byte, expires time.Time) (err error)
+	GetSession(ctx context.Context, tokenHash []byte) (nickname string, err error)
+	DeleteSession(ctx context.Context, tokenHash []byte) (err error)
+}
+
+type storage struct {
+	db *pgx.ConnPool
+}
+
+/* constructor */
+func NewStorage(db *pgx.ConnPool) Storage {
+	return &storage{
+		db: db,
+	}
+}
+
+var (
+	updatePassword = "UPDATE users SET password_hash = $2 WHERE nickname = $1"
+	selectPassword = "SELECT nickname, password_hash FROM users WHERE nickname = $1"
+
+	deleteExpiredSessions = "DELETE FROM sessions WHERE expires < now()"
+	insertSession         = "INSERT INTO sessions (token_hash, userID, expires) SELECT $2, u.ID, $3 FROM users u WHERE u.nickname = $1"
+	selectSession         = "SELECT u.nickname FROM sessions s JOIN users u ON u.ID = s.userID WHERE s.token_hash = $1 AND s.expires > now()"
+	deleteSession         = "DELETE FROM sessions WHERE token_hash = $1"
+)
+
+func (s *storage) SetPassword(ctx context.Context, nickname string, hash []byte) (err error) {
+	tag, err := s.db.ExecEx(ctx, updatePassword, nil, nickname, hash)
+	if err != nil {
+		logger.FromContext(ctx).Error("query failed", "op", "authStorage.SetPassword", "err", err)
+		return models.ErrInternal.Wrap(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrNotFound.WithMessage("cannot find user")
+	}
+
+	return
+}
+
+/* hash is nil for users that never set a password */
+func (s *storage) GetPassword(ctx context.Context, nickname string) (user string, hash []byte, err error) {
+	err = s.db.QueryRowEx(ctx, selectPassword, nil, nickname).Scan(&user, &hash)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return user, hash, models.ErrNotFound.WithMessage("cannot find user")
+		}
+		return user, hash, models.ErrInternal.Wrap(err)
+	}
+
+	return
+}
+
+func (s *storage) CreateSession(ctx context.Context, nickname string, tokenHash []byte, expires time.Time) (err error) {
+	/* logins are rare next to reads, a good moment to sweep sessions nobody will present again */
+	if _, err = s.db.ExecEx(ctx, deleteExpiredSessions, nil); err != nil {
+		logger.FromContext(ctx).Error("query failed", "op", "authStorage.CreateSession", "err", err)
+		return models.ErrInternal.Wrap(err)
+	}
+
+	tag, err := s.db.ExecEx(ctx, insertSession, nil, nickname, tokenHash, expires)
+	if err != nil {
+		logger.FromContext(ctx).Error("query failed", "op", "authStorage.CreateSession", "err", err)
+		return models.ErrInternal.Wrap(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrNotFound.WithMessage("cannot find user")
+	}
+
+	return
+}
+
+func (s *storage) GetSession(ctx context.Context, tokenHash []byte) (nickname string, err error) {
+	err = s.db.QueryRowEx(ctx, selectSession, nil, tokenHash).Scan(&nickname)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nickname, models.ErrUnauthorized.WithCode("invalid_token").WithMessage("invalid or expired token")
+		}
+		return nickname, models.ErrInternal.Wrap(err)
+	}
+
+	return
+}
+
+func (s *storage) DeleteSession(ctx context.Context, tokenHash []byte) (err error) {
+	if _, err = s.db.ExecEx(ctx, deleteSession, nil, tokenHash); err != nil {
+		logger.FromContext(ctx).Error("query failed", "op", "authStorage.DeleteSession", "err", err)
+		return models.ErrInternal.Wrap(err)
+	}
+
+	return
+}
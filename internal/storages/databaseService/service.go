@@ -1,13 +1,26 @@
 package databaseService
 
 import (
+	"context"
+	"fmt"
 	"github.com/jackc/pgx"
+	"github.com/pringleskate/tp_db_forum/internal/logger"
+	"github.com/pringleskate/tp_db_forum/internal/migrations"
 	"github.com/pringleskate/tp_db_forum/internal/models"
+	"strconv"
+	"strings"
+	"time"
 )
 
+/* an orchestrator probes often, a check that takes longer than this counts as failed */
+const readyTimeout = 2 * time.Second
+
 type Service interface {
-	Clear() (err error)
-	Status() (status models.Status, err error)
+	Clear(ctx context.Context) (err error)
+	ClearForum(ctx context.Context, slug string) (err error)
+	Status(ctx context.Context) (status models.Status, err error)
+	Ready(ctx context.Context) (checks []models.HealthCheck)
+	ReconcileCounters(ctx context.Context, fix bool) (result models.Reconciliation, err error)
 }
 
 type service struct {
@@ -22,20 +35,242 @@ func NewStorage(db *pgx.ConnPool) Service {
 	}
 }
 
-func (s *service) Clear() (err error) {
-	_, err = s.db.Exec("TRUNCATE users, forums, threads, posts, forum_users, votes CASCADE")
+func (s *service) Clear(ctx context.Context) (err error) {
+	_, err = s.db.ExecEx(ctx, "TRUNCATE users, forums, threads, posts, forum_users, votes CASCADE", nil)
 	if err != nil {
-		return models.Error{Code: "500"}
+		logger.FromContext(ctx).Error("query failed", "op", "databaseService.Clear", "err", err)
+		return models.ErrInternal.Wrap(err)
 	}
 	return
 }
 
-func (s *service) Status() (status models.Status, err error) {
-	err = s.db.QueryRow("SELECT (SELECT COUNT(*) FROM forums), (SELECT COUNT(*) FROM threads), (SELECT COUNT(*) FROM posts), (SELECT COUNT(*) FROM users)").
+var (
+	lockForum         = "SELECT ID FROM forums WHERE slug = $1 FOR UPDATE"
+	deleteForumVotes  = "DELETE FROM votes WHERE thread IN (SELECT t.ID FROM threads t JOIN forums f ON f.slug = t.forum WHERE f.ID = $1)"
+	deleteForumPosts  = "DELETE FROM posts WHERE forum = (SELECT slug FROM forums WHERE ID = $1)"
+	deleteForumThread = "DELETE FROM threads WHERE forum = (SELECT slug FROM forums WHERE ID = $1)"
+	deleteForumUsers  = "DELETE FROM forum_users WHERE forumID = $1"
+	deleteForum       = "DELETE FROM forums WHERE ID = $1"
+)
+
+/* wipes one forum with its threads, posts and votes, users stay */
+func (s *service) ClearForum(ctx context.Context, slug string) (err error) {
+	tx, err := s.db.BeginEx(ctx, nil)
+	if err != nil {
+		return models.ErrInternal.Wrap(err)
+	}
+	defer tx.Rollback()
+
+	var forumID int
+	if err = tx.QueryRowEx(ctx, lockForum, nil, slug).Scan(&forumID); err != nil {
+		if err == pgx.ErrNoRows {
+			return models.ErrNotFound.WithMessage("cannot find forum")
+		}
+		return models.ErrInternal.Wrap(err)
+	}
+
+	/* forum_moderators rows go with the forum through ON DELETE CASCADE */
+	for _, query := range []string{deleteForumVotes, deleteForumPosts, deleteForumThread, deleteForumUsers, deleteForum} {
+		if _, err = tx.ExecEx(ctx, query, nil, forumID); err != nil {
+			logger.FromContext(ctx).Error("query failed", "op", "databaseService.ClearForum", "err", err)
+			return models.ErrInternal.Wrap(err)
+		}
+	}
+
+	if err = tx.CommitEx(ctx); err != nil {
+		logger.FromContext(ctx).Error("query failed", "op", "databaseService.ClearForum", "err", err)
+		return models.ErrInternal.Wrap(err)
+	}
+	return
+}
+
+func (s *service) Status(ctx context.Context) (status models.Status, err error) {
+	err = s.db.QueryRowEx(ctx, "SELECT (SELECT COUNT(*) FROM forums), (SELECT COUNT(*) FROM threads), (SELECT COUNT(*) FROM posts), (SELECT COUNT(*) FROM users)", nil).
 				Scan(&status.Forum, &status.Thread, &status.Post, &status.User)
 	if err != nil && err != pgx.ErrNoRows {
-		return status, models.Error{Code: "500"}
+		return status, models.ErrInternal.Wrap(err)
 	}
 
 	return
-}
\ No newline at end of file
+}
+
+/* pool, ping and migrations in that order, a failed check skips the ones depending on it */
+func (s *service) Ready(ctx context.Context) (checks []models.HealthCheck) {
+	ctx, cancel := context.WithTimeout(ctx, readyTimeout)
+	defer cancel()
+
+	stat := s.db.Stat()
+	pool := models.HealthCheck{
+		Name:   "pool",
+		Detail: fmt.Sprintf("%d of %d connections in use", stat.CurrentConnections-stat.AvailableConnections, stat.MaxConnections),
+	}
+	conn, err := s.db.AcquireEx(ctx)
+	checks = append(checks, result(pool, err))
+	if err != nil {
+		return append(checks,
+			models.HealthCheck{Name: "ping", Status: models.HealthSkip},
+			models.HealthCheck{Name: "migrations", Status: models.HealthSkip})
+	}
+	defer s.db.Release(conn)
+
+	err = conn.Ping(ctx)
+	checks = append(checks, result(models.HealthCheck{Name: "ping"}, err))
+	if err != nil {
+		return append(checks, models.HealthCheck{Name: "migrations", Status: models.HealthSkip})
+	}
+
+	pending, err := migrations.Pending(conn)
+	if err == nil && len(pending) != 0 {
+		versions := make([]string, 0, len(pending))
+		for _, migration := range pending {
+			versions = append(versions, strconv.Itoa(migration.Version))
+		}
+		err = fmt.Errorf("pending versions %s", strings.Join(versions, ", "))
+	}
+	checks = append(checks, result(models.HealthCheck{Name: "migrations"}, err))
+
+	return checks
+}
+
+func result(check models.HealthCheck, err error) models.HealthCheck {
+	check.Status = models.HealthOK
+	if err != nil {
+		check.Status = models.HealthFail
+		check.Error = err.Error()
+	}
+	return check
+}
+
+/*
+	Each counter comes with the query listing where it drifted and the update recomputing it.
+	The check queries return id, forum, thread, post, stored and actual value,
+	limited to the ids in $1 unless it is NULL.
+*/
+type counter struct {
+	name   string
+	table  string
+	check  string
+	update string
+}
+
+var counters = []counter{
+	{
+		name:  models.CounterForumThreads,
+		table: "forums",
+		check: `SELECT f.ID, f.slug, 0, 0, f.threads, c.actual
+			FROM forums f
+			CROSS JOIN LATERAL (SELECT COUNT(*)::integer AS actual FROM threads t WHERE t.forum = f.slug) c
+			WHERE f.threads <> c.actual AND ($1::integer[] IS NULL OR f.ID = ANY($1))
+			ORDER BY f.ID`,
+		update: "UPDATE forums f SET threads = (SELECT COUNT(*) FROM threads t WHERE t.forum = f.slug) WHERE f.ID = ANY($1)",
+	},
+	{
+		name:  models.CounterForumPosts,
+		table: "forums",
+		check: `SELECT f.ID, f.slug, 0, 0, f.posts, c.actual
+			FROM forums f
+			CROSS JOIN LATERAL (SELECT COUNT(*)::integer AS actual FROM posts p WHERE p.forum = f.slug AND NOT p.deleted) c
+			WHERE f.posts <> c.actual AND ($1::integer[] IS NULL OR f.ID = ANY($1))
+			ORDER BY f.ID`,
+		update: "UPDATE forums f SET posts = (SELECT COUNT(*) FROM posts p WHERE p.forum = f.slug AND NOT p.deleted) WHERE f.ID = ANY($1)",
+	},
+	{
+		name:  models.CounterThreadVotes,
+		table: "threads",
+		check: `SELECT t.ID, t.forum, t.ID, 0, COALESCE(t.votes, 0), c.actual
+			FROM threads t
+			CROSS JOIN LATERAL (SELECT COALESCE(SUM(CASE WHEN v.voice THEN 1 ELSE -1 END), 0)::integer AS actual FROM votes v WHERE v.thread = t.ID) c
+			WHERE t.votes IS DISTINCT FROM c.actual AND ($1::integer[] IS NULL OR t.ID = ANY($1))
+			ORDER BY t.ID`,
+		update: "UPDATE threads t SET votes = (SELECT COALESCE(SUM(CASE WHEN v.voice THEN 1 ELSE -1 END), 0) FROM votes v WHERE v.thread = t.ID) WHERE t.ID = ANY($1)",
+	},
+	{
+		name:  models.CounterPostVotes,
+		table: "posts",
+		check: `SELECT p.ID, p.forum, p.thread, p.ID, p.votes, c.actual
+			FROM posts p
+			CROSS JOIN LATERAL (SELECT COALESCE(SUM(CASE WHEN v.voice THEN 1 ELSE -1 END), 0)::integer AS actual FROM post_votes v WHERE v.post = p.ID) c
+			WHERE p.votes <> c.actual AND ($1::integer[] IS NULL OR p.ID = ANY($1))
+			ORDER BY p.ID`,
+		update: "UPDATE posts p SET votes = (SELECT COALESCE(SUM(CASE WHEN v.voice THEN 1 ELSE -1 END), 0) FROM post_votes v WHERE v.post = p.ID) WHERE p.ID = ANY($1)",
+	},
+}
+
+/*
+	A counter and the rows it summarizes are written in the same transaction, so a single check
+	query sees them agree unless they really drifted. Fixing locks the drifted rows first and only
+	then recounts: a writer that already moved the counter has committed by then, one that has not
+	waits for the lock and applies its change on top of the recount.
+*/
+func (s *service) ReconcileCounters(ctx context.Context, fix bool) (result models.Reconciliation, err error) {
+	result.Drifts = make([]models.CounterDrift, 0)
+	for _, c := range counters {
+		var drifts []models.CounterDrift
+		if fix {
+			drifts, err = s.fixCounter(ctx, c)
+		} else {
+			drifts, _, err = checkCounter(ctx, s.db, c, nil)
+		}
+		if err != nil {
+			logger.FromContext(ctx).Error("query failed", "op", "databaseService.ReconcileCounters", "counter", c.name, "err", err)
+			return result, models.ErrInternal.Wrap(err)
+		}
+		result.Drifts = append(result.Drifts, drifts...)
+	}
+	result.Fixed = fix
+	return result, nil
+}
+
+/* one transaction per counter, so no lock on forums is held while threads get locked */
+func (s *service) fixCounter(ctx context.Context, c counter) ([]models.CounterDrift, error) {
+	drifts, ids, err := checkCounter(ctx, s.db, c, nil)
+	if err != nil || len(drifts) == 0 {
+		return drifts, err
+	}
+
+	tx, err := s.db.BeginEx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	lock := fmt.Sprintf("SELECT ID FROM %s WHERE ID = ANY($1) ORDER BY ID FOR UPDATE", c.table)
+	if _, err = tx.ExecEx(ctx, lock, nil, ids); err != nil {
+		return nil, err
+	}
+
+	/* another reconciliation may have fixed some of them in the meantime */
+	drifts, ids, err = checkCounter(ctx, tx, c, ids)
+	if err != nil || len(drifts) == 0 {
+		return drifts, err
+	}
+	if _, err = tx.ExecEx(ctx, c.update, nil, ids); err != nil {
+		return nil, err
+	}
+
+	return drifts, tx.CommitEx(ctx)
+}
+
+/* both the pool and a transaction */
+type querier interface {
+	QueryEx(ctx context.Context, sql string, options *pgx.QueryExOptions, args ...interface{}) (*pgx.Rows, error)
+}
+
+func checkCounter(ctx context.Context, db querier, c counter, ids []int32) (drifts []models.CounterDrift, drifted []int32, err error) {
+	rows, err := db.QueryEx(ctx, c.check, nil, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int32
+		drift := models.CounterDrift{Counter: c.name}
+		if err = rows.Scan(&id, &drift.Forum, &drift.Thread, &drift.Post, &drift.Stored, &drift.Actual); err != nil {
+			return nil, nil, err
+		}
+		drifts = append(drifts, drift)
+		drifted = append(drifted, id)
+	}
+	return drifts, drifted, rows.Err()
+}
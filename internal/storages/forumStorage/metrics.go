@@ -0,0 +1,51 @@
+package forumStorage
+
+import (
+	"context"
+	"github.com/pringleskate/tp_db_forum/internal/metrics"
+	"github.com/pringleskate/tp_db_forum/internal/models"
+	"time"
+)
+
+/* times every call of the wrapped forum storage, postgres or memory alike */
+type measured struct {
+	storage Storage
+	queries *metrics.Queries
+}
+
+func WithMetrics(storage Storage, queries *metrics.Queries) Storage {
+	return &measured{
+		storage: storage,
+		queries: queries,
+	}
+}
+
+func (m *measured) CreateForum(ctx context.Context, forumSlug models.ForumCreate) (forum models.Forum, err error) {
+	defer m.queries.Observe("forum", "CreateForum", time.Now(), &err)
+	return m.storage.CreateForum(ctx, forumSlug)
+}
+
+func (m *measured) GetDetails(ctx context.Context, forumSlug models.ForumInput) (forum models.Forum, err error) {
+	defer m.queries.Observe("forum", "GetDetails", time.Now(), &err)
+	return m.storage.GetDetails(ctx, forumSlug)
+}
+
+func (m *measured) CheckIfForumExists(ctx context.Context, input models.ForumInput) (err error) {
+	defer m.queries.Observe("forum", "CheckIfForumExists", time.Now(), &err)
+	return m.storage.CheckIfForumExists(ctx, input)
+}
+
+func (m *measured) GetForumID(ctx context.Context, input models.ForumInput) (ID int, err error) {
+	defer m.queries.Observe("forum", "GetForumID", time.Now(), &err)
+	return m.storage.GetForumID(ctx, input)
+}
+
+func (m *measured) GetForumForPost(ctx context.Context, forumSlug string, forum *models.Forum) (err error) {
+	defer m.queries.Observe("forum", "GetForumForPost", time.Now(), &err)
+	return m.storage.GetForumForPost(ctx, forumSlug, forum)
+}
+
+func (m *measured) GetForums(ctx context.Context, input models.ForumList) (forums []models.Forum, err error) {
+	defer m.queries.Observe("forum", "GetForums", time.Now(), &err)
+	return m.storage.GetForums(ctx, input)
+}
@@ -1,22 +1,21 @@
 package forumStorage
 
 import (
+	"context"
 	"fmt"
 	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx"
+	"github.com/pringleskate/tp_db_forum/internal/logger"
 	"github.com/pringleskate/tp_db_forum/internal/models"
 )
 
 type Storage interface {
-	CreateForum(forumSlug models.ForumCreate) (forum models.Forum, err error)
-	GetDetails(forumSlug models.ForumInput) (forum models.Forum, err error)
-	UpdateThreadsCount(input models.ForumInput) (err error)
-	UpdatePostsCount(input models.ForumInput, posts int) (err error)
-	//UpdatePostsCount(input models.ForumInput) (err error)
-	AddUserToForum(userID int, forumID int) (err error)
-	CheckIfForumExists(input models.ForumInput) (err error)
-	GetForumID(input models.ForumInput) (ID int, err error)
-	GetForumForPost(forumSlug string, forum *models.Forum) (err error)
+	CreateForum(ctx context.Context, forumSlug models.ForumCreate) (forum models.Forum, err error)
+	GetDetails(ctx context.Context, forumSlug models.ForumInput) (forum models.Forum, err error)
+	CheckIfForumExists(ctx context.Context, input models.ForumInput) (err error)
+	GetForumID(ctx context.Context, input models.ForumInput) (ID int, err error)
+	GetForumForPost(ctx context.Context, forumSlug string, forum *models.Forum) (err error)
+	GetForums(ctx context.Context, input models.ForumList) (forums []models.Forum, err error)
 }
 
 type storage struct {
@@ -30,36 +29,36 @@ func NewStorage(db *pgx.ConnPool) Storage {
 	}
 }
 
-func (s *storage) CreateForum(forumSlug models.ForumCreate) (forum models.Forum, err error) {
-	err = s.db.QueryRow("INSERT INTO forums (slug, title, user_nick) VALUES ($1, $2,(SELECT u.nickname FROM users u WHERE u.nickname = $3)) RETURNING slug, title, user_nick",
+func (s *storage) CreateForum(ctx context.Context, forumSlug models.ForumCreate) (forum models.Forum, err error) {
+	err = s.db.QueryRowEx(ctx, "INSERT INTO forums (slug, title, user_nick) VALUES ($1, $2,(SELECT u.nickname FROM users u WHERE u.nickname = $3)) RETURNING slug, title, user_nick", nil,
 						forumSlug.Slug, forumSlug.Title, forumSlug.User).Scan(&forum.Slug, &forum.Title, &forum.User)
 
 	if pqErr, ok := err.(pgx.PgError); ok {
 		switch pqErr.Code {
 		case pgerrcode.UniqueViolation:
-			return forum, models.Error{Code: "409"}
+			return forum, models.ErrConflict
 		case pgerrcode.NotNullViolation, pgerrcode.ForeignKeyViolation:
-			return forum, models.Error{Code: "404"}
+			return forum, models.ErrNotFound
 		default:
-			fmt.Println(err)
-			return forum, models.Error{Code: "500"}
+			logger.FromContext(ctx).Error("query failed", "op", "forumStorage.CreateForum", "err", err)
+			return forum, models.ErrInternal.Wrap(err)
 		}
 	}
 
 	return forum, nil
 }
 
-func (s *storage) GetDetails(forumSlug models.ForumInput) (forum models.Forum, err error) {
-	err = s.db.QueryRow("SELECT slug, title, threads, posts, user_nick FROM forums WHERE slug = $1", forumSlug.Slug).
+func (s *storage) GetDetails(ctx context.Context, forumSlug models.ForumInput) (forum models.Forum, err error) {
+	err = s.db.QueryRowEx(ctx, "SELECT slug, title, threads, posts, user_nick FROM forums WHERE slug = $1", nil, forumSlug.Slug).
 				Scan(&forum.Slug, &forum.Title, &forum.Threads, &forum.Posts, &forum.User)
 
 	if err != nil {
-		fmt.Println(err)
 		if err == pgx.ErrNoRows {
-			return forum, models.Error{Code: "404"}
+			return forum, models.ErrNotFound
 
 		}
-		return forum, models.Error{Code: "500"}
+		logger.FromContext(ctx).Error("query failed", "op", "forumStorage.GetDetails", "err", err)
+		return forum, models.ErrInternal.Wrap(err)
 	}
 
 	return forum, nil
@@ -67,81 +66,95 @@ func (s *storage) GetDetails(forumSlug models.ForumInput) (forum models.Forum, e
 
 //TODO 2v можно сделать в userstorage один запрос с джоинами
 
-func (s *storage) UpdateThreadsCount(input models.ForumInput) (err error) {
-	_, err = s.db.Exec("UPDATE forums SET threads = threads + 1 WHERE slug = $1", input.Slug)
-	if err != nil {
-		fmt.Println(err)
-		return models.Error{Code: "500"}
-	}
-	return
-}
-
-func (s *storage) UpdatePostsCount(input models.ForumInput, posts int) (err error) {
-	_, err = s.db.Exec("UPDATE forums SET posts = posts + $2 WHERE slug = $1", input.Slug, posts)
-	if err != nil {
-		fmt.Println(err)
-		return models.Error{Code: "500"}
-	}
-	return
-}
-/*
-func (s *storage) UpdatePostsCount(input models.ForumInput) (err error) {
-	_, err = s.db.Exec("UPDATE forums SET posts = posts + 1 WHERE slug = $1", input.Slug)
-	if err != nil {
-		fmt.Println(err)
-		return models.Error{Code: "500"}
-	}
-	return
-}*/
-
-func (s *storage) AddUserToForum(userID int, forumID int) (err error) {
-	_, err = s.db.Exec("INSERT INTO forum_users (forumID, userID) VALUES ($1, $2)", forumID, userID)
+func (s *storage) CheckIfForumExists(ctx context.Context, input models.ForumInput) (err error) {
+	var ID int
+	err = s.db.QueryRowEx(ctx, "SELECT ID from forums WHERE slug = $1", nil, input.Slug).Scan(&ID)
 	if err != nil {
-		if pqErr, ok := err.(pgx.PgError); ok {
-			switch pqErr.Code {
-			case pgerrcode.UniqueViolation:
-				return models.Error{Code: "409"}
-			}
+		if err == pgx.ErrNoRows {
+			return models.ErrNotFound
 		}
-		return models.Error{Code: "500"}
+		return models.ErrInternal.Wrap(err)
 	}
 
 	return
 }
 
-func (s *storage) CheckIfForumExists(input models.ForumInput) (err error) {
-	var ID int
-	err = s.db.QueryRow("SELECT ID from forums WHERE slug = $1", input.Slug).Scan(&ID)
+func (s storage) GetForumID(ctx context.Context, input models.ForumInput) (ID int, err error) {
+	err = s.db.QueryRowEx(ctx, "SELECT ID from forums WHERE slug = $1", nil, input.Slug).Scan(&ID)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return models.Error{Code: "404"}
+			return ID, models.ErrNotFound
 		}
-		return models.Error{Code: "500"}
+		return ID, models.ErrInternal.Wrap(err)
 	}
 
 	return
 }
 
-func (s storage) GetForumID(input models.ForumInput) (ID int, err error) {
-	err = s.db.QueryRow("SELECT ID from forums WHERE slug = $1", input.Slug).Scan(&ID)
+func (s *storage) GetForumForPost(ctx context.Context, forumSlug string, forum *models.Forum) (err error) {
+	forum.Slug = forumSlug
+	err = s.db.QueryRowEx(ctx, "SELECT title, threads, posts, user_nick FROM forums WHERE slug = $1", nil, forumSlug).
+		Scan(&forum.Title, &forum.Threads, &forum.Posts, &forum.User)
+
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return ID, models.Error{Code: "404"}
+			return models.ErrNotFound
 		}
-		return ID, models.Error{Code: "500"}
+		return models.ErrInternal.Wrap(err)
 	}
 
 	return
 }
 
-func (s *storage) GetForumForPost(forumSlug string, forum *models.Forum) (err error) {
-	forum.Slug = forumSlug
-	err = s.db.QueryRow("SELECT title, threads, posts, user_nick FROM forums WHERE slug = $1", forumSlug).
-		Scan(&forum.Title, &forum.Threads, &forum.Posts, &forum.User)
+/* sort keys accepted by GetForums, slug breaks ties so the keyset is unique */
+var sortColumns = map[string]string{
+	"":         "slug",
+	"slug":     "slug",
+	"threads":  "threads",
+	"posts":    "posts",
+	"activity": "activity",
+}
+
+const selectForums = `
+	SELECT slug, title, threads, posts, user_nick
+	FROM forums
+	WHERE ($1 = '' OR strpos(lower(title), lower($1)) > 0)
+	  AND ($2 = '' OR (%[1]s, slug) %[2]s (SELECT %[1]s, slug FROM forums WHERE slug = $2::citext))
+	ORDER BY %[1]s %[3]s, slug %[3]s
+	LIMIT $3
+`
+
+func (s *storage) GetForums(ctx context.Context, input models.ForumList) (forums []models.Forum, err error) {
+	column, ok := sortColumns[input.Sort]
+	if !ok {
+		return forums, models.ErrValidation.WithCode("unknown_sort").WithMessage("unknown sort")
+	}
 
+	query := fmt.Sprintf(selectForums, column, ">", "ASC")
+	if input.Desc {
+		query = fmt.Sprintf(selectForums, column, "<", "DESC")
+	}
+
+	rows, err := s.db.QueryEx(ctx, query, nil, input.Title, input.Since, input.Limit)
 	if err != nil {
-		return models.Error{Code: "500"}
+		logger.FromContext(ctx).Error("query failed", "op", "forumStorage.GetForums", "err", err)
+		return forums, models.ErrInternal.Wrap(err)
+	}
+	defer rows.Close()
+
+	forums = make([]models.Forum, 0)
+	for rows.Next() {
+		forum := models.Forum{}
+		err = rows.Scan(&forum.Slug, &forum.Title, &forum.Threads, &forum.Posts, &forum.User)
+		if err != nil {
+			return forums, models.ErrInternal.Wrap(err)
+		}
+		forums = append(forums, forum)
 	}
 
-	return
-}
\ No newline at end of file
+	if err = rows.Err(); err != nil {
+		return forums, models.ErrInternal.Wrap(err)
+	}
+
+	return forums, nil
+}
@@ -0,0 +1,83 @@
+package memoryStorage
+
+import (
+	"context"
+	"github.com/pringleskate/tp_db_forum/internal/models"
+	"github.com/pringleskate/tp_db_forum/internal/storages/authStorage"
+	"time"
+)
+
+type authStore struct {
+	db *Database
+}
+
+func NewAuthStorage(db *Database) authStorage.Storage {
+	return &authStore{
+		db: db,
+	}
+}
+
+func (s *authStore) SetPassword(ctx context.Context, nickname string, hash []byte) (err error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	id, ok := s.db.userID(nickname)
+	if !ok {
+		return models.ErrNotFound.WithMessage("cannot find user")
+	}
+
+	s.db.passwords[id] = hash
+	return
+}
+
+func (s *authStore) GetPassword(ctx context.Context, nickname string) (user string, hash []byte, err error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	id, ok := s.db.userID(nickname)
+	if !ok {
+		return user, hash, models.ErrNotFound.WithMessage("cannot find user")
+	}
+
+	return s.db.users[id].Nickname, s.db.passwords[id], nil
+}
+
+func (s *authStore) CreateSession(ctx context.Context, nickname string, tokenHash []byte, expires time.Time) (err error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	id, ok := s.db.userID(nickname)
+	if !ok {
+		return models.ErrNotFound.WithMessage("cannot find user")
+	}
+
+	now := time.Now()
+	for token, session := range s.db.sessions {
+		if session.expires.Before(now) {
+			delete(s.db.sessions, token)
+		}
+	}
+
+	s.db.sessions[string(tokenHash)] = session{userID: id, expires: expires}
+	return
+}
+
+func (s *authStore) GetSession(ctx context.Context, tokenHash []byte) (nickname string, err error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	session, ok := s.db.sessions[string(tokenHash)]
+	if !ok || !session.expires.After(time.Now()) {
+		return nickname, models.ErrUnauthorized.WithCode("invalid_token").WithMessage("invalid or expired token")
+	}
+
+	return s.db.users[session.userID].Nickname, nil
+}
+
+func (s *authStore) DeleteSession(ctx context.Context, tokenHash []byte) (err error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	delete(s.db.sessions, string(tokenHash))
+	return
+}
@@ -0,0 +1,315 @@
+package memoryStorage
+
+import (
+	"context"
+	"github.com/pringleskate/tp_db_forum/internal/models"
+	"github.com/pringleskate/tp_db_forum/internal/storages/databaseService"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+Database keeps every table in process memory behind one lock.
+Storages built on the same Database see each other's writes the way the postgres ones share a pool.
+Keys of the *By* indexes are lower-cased to mimic citext columns.
+*/
+type Database struct {
+	mu sync.RWMutex
+
+	users        map[int]*models.User
+	usersByNick  map[string]int
+	usersByEmail map[string]int
+	passwords    map[int][]byte
+	roles        map[int]string
+	nextUserID   int
+
+	sessions map[string]session
+
+	forums       map[int]*forum
+	forumsBySlug map[string]int
+	forumUsers   map[int]map[int]bool
+	moderators   map[int]map[int]bool
+	nextForumID  int
+
+	threads       map[int]*models.Thread
+	threadsBySlug map[string]int
+	nextThreadID  int
+
+	posts         map[int]*post
+	postsByThread map[int][]int
+	nextPostID    int
+	revisions     map[int][]models.PostRevision
+
+	votes map[voteKey]bool
+	/* post id -> lower-cased nickname -> voice */
+	postVotes map[int]map[string]bool
+}
+
+type forum struct {
+	models.Forum
+	activity time.Time
+}
+
+type post struct {
+	models.Post
+	path []int
+}
+
+type session struct {
+	userID  int
+	expires time.Time
+}
+
+type voteKey struct {
+	nickname string
+	thread   int
+}
+
+/* constructor */
+func NewDatabase() *Database {
+	db := &Database{}
+	db.reset()
+	return db
+}
+
+func (db *Database) reset() {
+	db.users = make(map[int]*models.User)
+	db.usersByNick = make(map[string]int)
+	db.usersByEmail = make(map[string]int)
+	db.passwords = make(map[int][]byte)
+	db.roles = make(map[int]string)
+	db.nextUserID = 1
+
+	db.sessions = make(map[string]session)
+
+	db.forums = make(map[int]*forum)
+	db.forumsBySlug = make(map[string]int)
+	db.forumUsers = make(map[int]map[int]bool)
+	db.moderators = make(map[int]map[int]bool)
+	db.nextForumID = 1
+
+	db.threads = make(map[int]*models.Thread)
+	db.threadsBySlug = make(map[string]int)
+	db.nextThreadID = 1
+
+	db.posts = make(map[int]*post)
+	db.postsByThread = make(map[int][]int)
+	db.nextPostID = 1
+	db.revisions = make(map[int][]models.PostRevision)
+
+	db.votes = make(map[voteKey]bool)
+	db.postVotes = make(map[int]map[string]bool)
+}
+
+func key(s string) string {
+	return strings.ToLower(s)
+}
+
+func (db *Database) userID(nickname string) (int, bool) {
+	id, ok := db.usersByNick[key(nickname)]
+	return id, ok
+}
+
+func (db *Database) forumID(slug string) (int, bool) {
+	id, ok := db.forumsBySlug[key(slug)]
+	return id, ok
+}
+
+/* resolves ThreadInput the same way the postgres storages do: by slug when it is set, by ID otherwise */
+func (db *Database) thread(input models.ThreadInput) (*models.Thread, bool) {
+	id := input.ThreadID
+	if input.Slug != "" {
+		var ok bool
+		if id, ok = db.threadsBySlug[key(input.Slug)]; !ok {
+			return nil, false
+		}
+	}
+	thread, ok := db.threads[id]
+	return thread, ok
+}
+
+/* assigns the next ID, caller holds the write lock */
+func (db *Database) insertForum(input models.Forum) int {
+	id := db.nextForumID
+	db.nextForumID++
+
+	db.forums[id] = &forum{Forum: input, activity: time.Now()}
+	db.forumsBySlug[key(input.Slug)] = id
+	return id
+}
+
+/* mirrors forums.activity: the newest thread or post creation time */
+func (f *forum) touch(at time.Time) {
+	if at.After(f.activity) {
+		f.activity = at
+	}
+}
+
+func (db *Database) addUserToForum(forumID int, userID int) {
+	users, ok := db.forumUsers[forumID]
+	if !ok {
+		users = make(map[int]bool)
+		db.forumUsers[forumID] = users
+	}
+	users[userID] = true
+}
+
+type service struct {
+	db *Database
+}
+
+func NewDatabaseService(db *Database) databaseService.Service {
+	return &service{
+		db: db,
+	}
+}
+
+func (s *service) Clear(ctx context.Context) (err error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	s.db.reset()
+	return
+}
+
+func (s *service) ClearForum(ctx context.Context, slug string) (err error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	forumID, ok := s.db.forumID(slug)
+	if !ok {
+		return models.ErrNotFound.WithMessage("cannot find forum")
+	}
+	forumSlug := key(s.db.forums[forumID].Slug)
+
+	for id, thread := range s.db.threads {
+		if key(thread.Forum) != forumSlug {
+			continue
+		}
+		for vote := range s.db.votes {
+			if vote.thread == id {
+				delete(s.db.votes, vote)
+			}
+		}
+		for _, postID := range s.db.postsByThread[id] {
+			s.db.dropPost(postID)
+		}
+		delete(s.db.postsByThread, id)
+		delete(s.db.threads, id)
+		if thread.Slug != "" {
+			delete(s.db.threadsBySlug, key(thread.Slug))
+		}
+	}
+
+	delete(s.db.forumUsers, forumID)
+	delete(s.db.moderators, forumID)
+	delete(s.db.forums, forumID)
+	delete(s.db.forumsBySlug, forumSlug)
+	return
+}
+
+func (s *service) Status(ctx context.Context) (status models.Status, err error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	status.Forum = int32(len(s.db.forums))
+	status.Thread = int32(len(s.db.threads))
+	status.Post = int64(len(s.db.posts))
+	status.User = int32(len(s.db.users))
+	return
+}
+
+/* nothing to reach over the network, the data lives as long as the process */
+func (s *service) Ready(ctx context.Context) (checks []models.HealthCheck) {
+	return []models.HealthCheck{{Name: "storage", Status: models.HealthOK, Detail: "memory"}}
+}
+
+/* recounted under one lock, so nothing can move while the counters are compared and fixed */
+func (s *service) ReconcileCounters(ctx context.Context, fix bool) (result models.Reconciliation, err error) {
+	if fix {
+		s.db.mu.Lock()
+		defer s.db.mu.Unlock()
+	} else {
+		s.db.mu.RLock()
+		defer s.db.mu.RUnlock()
+	}
+
+	threads := make(map[string]int)
+	posts := make(map[string]int)
+	for _, thread := range s.db.threads {
+		threads[key(thread.Forum)]++
+	}
+	for _, p := range s.db.posts {
+		if !p.IsDeleted {
+			posts[key(p.Forum)]++
+		}
+	}
+	threadVotes := make(map[int]int)
+	for vote, voice := range s.db.votes {
+		threadVotes[vote.thread] += voiceValue(voice)
+	}
+
+	result.Drifts = make([]models.CounterDrift, 0)
+	drifted := func(drift models.CounterDrift) bool {
+		if drift.Stored == drift.Actual {
+			return false
+		}
+		result.Drifts = append(result.Drifts, drift)
+		return fix
+	}
+
+	for _, id := range sortedIDs(s.db.forums) {
+		f := s.db.forums[id]
+		if drifted(models.CounterDrift{Counter: models.CounterForumThreads, Forum: f.Slug, Stored: f.Threads, Actual: threads[key(f.Slug)]}) {
+			f.Threads = threads[key(f.Slug)]
+		}
+	}
+	for _, id := range sortedIDs(s.db.forums) {
+		f := s.db.forums[id]
+		if drifted(models.CounterDrift{Counter: models.CounterForumPosts, Forum: f.Slug, Stored: f.Posts, Actual: posts[key(f.Slug)]}) {
+			f.Posts = posts[key(f.Slug)]
+		}
+	}
+	for _, id := range sortedIDs(s.db.threads) {
+		t := s.db.threads[id]
+		if drifted(models.CounterDrift{Counter: models.CounterThreadVotes, Forum: t.Forum, Thread: t.ID, Stored: t.Votes, Actual: threadVotes[t.ID]}) {
+			t.Votes = threadVotes[t.ID]
+		}
+	}
+	for _, id := range sortedIDs(s.db.posts) {
+		p := s.db.posts[id]
+		actual := 0
+		for _, voice := range s.db.postVotes[p.ID] {
+			actual += voiceValue(voice)
+		}
+		if drifted(models.CounterDrift{Counter: models.CounterPostVotes, Forum: p.Forum, Thread: p.ThreadID, Post: p.ID, Stored: p.Votes, Actual: actual}) {
+			p.Votes = actual
+		}
+	}
+
+	result.Fixed = fix
+	return result, nil
+}
+
+/* the postgres storage reports drifts in ID order, map iteration has none */
+func sortedIDs(rows interface{}) []int {
+	ids := make([]int, 0)
+	switch rows := rows.(type) {
+	case map[int]*forum:
+		for id := range rows {
+			ids = append(ids, id)
+		}
+	case map[int]*models.Thread:
+		for id := range rows {
+			ids = append(ids, id)
+		}
+	case map[int]*post:
+		for id := range rows {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
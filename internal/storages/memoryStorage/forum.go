@@ -0,0 +1,163 @@
+package memoryStorage
+
+import (
+	"context"
+	"github.com/pringleskate/tp_db_forum/internal/models"
+	"github.com/pringleskate/tp_db_forum/internal/storages/forumStorage"
+	"sort"
+	"strings"
+)
+
+type forumStore struct {
+	db *Database
+}
+
+func NewForumStorage(db *Database) forumStorage.Storage {
+	return &forumStore{
+		db: db,
+	}
+}
+
+func (s *forumStore) CreateForum(ctx context.Context, forumSlug models.ForumCreate) (forum models.Forum, err error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	userID, ok := s.db.userID(forumSlug.User)
+	if !ok {
+		return forum, models.ErrNotFound
+	}
+	if _, ok := s.db.forumID(forumSlug.Slug); ok {
+		return forum, models.ErrConflict
+	}
+
+	forum = models.Forum{
+		Slug:  forumSlug.Slug,
+		Title: forumSlug.Title,
+		User:  s.db.users[userID].Nickname,
+	}
+	s.db.insertForum(forum)
+
+	return forum, nil
+}
+
+func (s *forumStore) GetDetails(ctx context.Context, forumSlug models.ForumInput) (forum models.Forum, err error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	id, ok := s.db.forumID(forumSlug.Slug)
+	if !ok {
+		return forum, models.ErrNotFound
+	}
+
+	return s.db.forums[id].Forum, nil
+}
+
+func (s *forumStore) CheckIfForumExists(ctx context.Context, input models.ForumInput) (err error) {
+	_, err = s.GetForumID(ctx, input)
+	return
+}
+
+func (s *forumStore) GetForumID(ctx context.Context, input models.ForumInput) (ID int, err error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	ID, ok := s.db.forumID(input.Slug)
+	if !ok {
+		return ID, models.ErrNotFound
+	}
+
+	return ID, nil
+}
+
+func (s *forumStore) GetForumForPost(ctx context.Context, forumSlug string, forum *models.Forum) (err error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	id, ok := s.db.forumID(forumSlug)
+	if !ok {
+		return models.ErrNotFound
+	}
+
+	*forum = s.db.forums[id].Forum
+	forum.Slug = forumSlug
+	return
+}
+
+func (s *forumStore) GetForums(ctx context.Context, input models.ForumList) (forums []models.Forum, err error) {
+	compare, ok := forumOrders[input.Sort]
+	if !ok {
+		return forums, models.ErrValidation.WithCode("unknown_sort").WithMessage("unknown sort")
+	}
+	if input.Desc {
+		asc := compare
+		compare = func(a, b *forum) int { return -asc(a, b) }
+	}
+
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	var since *forum
+	if input.Since != "" {
+		id, ok := s.db.forumID(input.Since)
+		if !ok {
+			return []models.Forum{}, nil
+		}
+		since = s.db.forums[id]
+	}
+
+	selected := make([]*forum, 0)
+	for _, f := range s.db.forums {
+		if !strings.Contains(strings.ToLower(f.Title), strings.ToLower(input.Title)) {
+			continue
+		}
+		if since != nil && compare(f, since) <= 0 {
+			continue
+		}
+		selected = append(selected, f)
+	}
+
+	sort.Slice(selected, func(i, j int) bool {
+		return compare(selected[i], selected[j]) < 0
+	})
+	if len(selected) > input.Limit {
+		selected = selected[:input.Limit]
+	}
+
+	forums = make([]models.Forum, 0, len(selected))
+	for _, f := range selected {
+		forums = append(forums, f.Forum)
+	}
+
+	return forums, nil
+}
+
+/* ascending orders for GetForums, each falls back to the slug like the postgres keyset does */
+var forumOrders = map[string]func(a, b *forum) int{
+	"":     compareSlugs,
+	"slug": compareSlugs,
+	"threads": func(a, b *forum) int {
+		if a.Threads != b.Threads {
+			return a.Threads - b.Threads
+		}
+		return compareSlugs(a, b)
+	},
+	"posts": func(a, b *forum) int {
+		if a.Posts != b.Posts {
+			return a.Posts - b.Posts
+		}
+		return compareSlugs(a, b)
+	},
+	"activity": func(a, b *forum) int {
+		if !a.activity.Equal(b.activity) {
+			if a.activity.Before(b.activity) {
+				return -1
+			}
+			return 1
+		}
+		return compareSlugs(a, b)
+	},
+}
+
+func compareSlugs(a, b *forum) int {
+	return strings.Compare(key(a.Slug), key(b.Slug))
+}
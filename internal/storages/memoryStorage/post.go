@@ -0,0 +1,415 @@
+package memoryStorage
+
+import (
+	"context"
+	"github.com/pringleskate/tp_db_forum/internal/models"
+	"github.com/pringleskate/tp_db_forum/internal/storages/postStorage"
+	"sort"
+	"time"
+)
+
+type postStore struct {
+	db *Database
+}
+
+func NewPostStorage(db *Database) postStorage.Storage {
+	return &postStore{
+		db: db,
+	}
+}
+
+func (s *postStore) CreatePosts(ctx context.Context, thread models.ThreadInput, forum string, created time.Time, posts []models.PostCreate) (post []models.Post, err error) {
+	post = make([]models.Post, 0, len(posts))
+	if len(posts) == 0 {
+		return post, nil
+	}
+
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	target, ok := s.db.threads[thread.ThreadID]
+	if !ok {
+		return nil, models.ErrNotFound.WithMessage("cannot find thread")
+	}
+	if target.IsArchived {
+		return nil, models.ErrConflict.WithCode("thread_archived").WithMessage("thread is archived")
+	}
+
+	authorIDs := make([]int, 0, len(posts))
+	for _, p := range posts {
+		userID, ok := s.db.userID(p.Author)
+		if !ok {
+			return nil, models.ErrNotFound.WithMessage("cannot find user")
+		}
+		authorIDs = append(authorIDs, userID)
+	}
+
+	for _, p := range posts {
+		if p.Parent == 0 {
+			continue
+		}
+		parent, ok := s.db.posts[p.Parent]
+		if !ok || parent.ThreadID != thread.ThreadID {
+			return nil, models.ErrConflict.WithMessage("Parent post was created in another thread")
+		}
+	}
+
+	forumID, ok := s.db.forumID(forum)
+	if !ok {
+		return nil, models.ErrNotFound.WithMessage("cannot find forum")
+	}
+
+	for i, p := range posts {
+		stored := s.db.insertPost(models.Post{
+			ThreadInput: models.ThreadInput{ThreadID: thread.ThreadID},
+			Parent:      p.Parent,
+			Author:      p.Author,
+			Message:     p.Message,
+			Forum:       forum,
+			Created:     created,
+		})
+		s.db.addUserToForum(forumID, authorIDs[i])
+		post = append(post, stored.Post)
+	}
+	s.db.forums[forumID].Posts += len(posts)
+	s.db.forums[forumID].touch(created)
+
+	return post, nil
+}
+
+/* removes the post with its revisions and votes, caller holds the write lock and fixes postsByThread */
+func (db *Database) dropPost(id int) {
+	delete(db.posts, id)
+	delete(db.revisions, id)
+	delete(db.postVotes, id)
+}
+
+/* assigns the next ID and materialized path, caller holds the write lock */
+func (db *Database) insertPost(input models.Post) *post {
+	id := db.nextPostID
+	db.nextPostID++
+
+	stored := &post{Post: input}
+	stored.ID = id
+	stored.IsEdited = false
+	if parent, ok := db.posts[input.Parent]; ok && input.Parent != 0 {
+		stored.path = append(append([]int{}, parent.path...), id)
+	} else {
+		stored.path = []int{id}
+	}
+
+	db.posts[id] = stored
+	db.postsByThread[input.ThreadID] = append(db.postsByThread[input.ThreadID], id)
+	return stored
+}
+
+func (s *postStore) CreatePost(ctx context.Context, input models.Post) (post models.Post, err error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if _, ok := s.db.userID(input.Author); !ok {
+		return post, models.ErrNotFound.WithMessage("conflict post")
+	}
+	if _, ok := s.db.forumID(input.Forum); !ok {
+		return post, models.ErrNotFound.WithMessage("conflict post")
+	}
+	if _, ok := s.db.threads[input.ThreadID]; !ok {
+		return post, models.ErrNotFound.WithMessage("conflict post")
+	}
+
+	return s.db.insertPost(input).Post, nil
+}
+
+func (s *postStore) GetPostDetails(ctx context.Context, input models.PostInput, post *models.Post) (err error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	stored, ok := s.db.posts[input.ID]
+	if !ok {
+		return models.ErrNotFound
+	}
+
+	*post = stored.Post
+	return
+}
+
+func (s *postStore) UpdatePost(ctx context.Context, input models.PostUpdate, editor string) (post models.Post, err error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	stored, ok := s.db.posts[input.ID]
+	if !ok {
+		return post, models.ErrNotFound
+	}
+
+	if stored.IsDeleted {
+		return post, models.ErrConflict.WithMessage("post is deleted")
+	}
+	if s.db.threads[stored.ThreadID].IsArchived {
+		return post, models.ErrConflict.WithCode("thread_archived").WithMessage("thread is archived")
+	}
+
+	if input.Message != "" && input.Message != stored.Message {
+		revisions := s.db.revisions[input.ID]
+		if len(revisions) == 0 {
+			revisions = append(revisions, models.PostRevision{Revision: 1, Message: stored.Message, Editor: stored.Author, Created: stored.Created})
+		}
+		s.db.revisions[input.ID] = append(revisions, models.PostRevision{
+			Revision: len(revisions) + 1,
+			Message:  input.Message,
+			Editor:   editor,
+			Created:  time.Now(),
+		})
+
+		stored.Message = input.Message
+		stored.IsEdited = true
+	}
+
+	return stored.Post, nil
+}
+
+func (s *postStore) GetPostRevisions(ctx context.Context, id int) (revisions []models.PostRevision, err error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	return append([]models.PostRevision{}, s.db.revisions[id]...), nil
+}
+
+func (s *postStore) DeletePost(ctx context.Context, input models.PostInput) (post models.Post, err error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	stored, ok := s.db.posts[input.ID]
+	if !ok {
+		return post, models.ErrNotFound
+	}
+
+	if s.db.threads[stored.ThreadID].IsArchived {
+		return post, models.ErrConflict.WithCode("thread_archived").WithMessage("thread is archived")
+	}
+
+	if !stored.IsDeleted {
+		stored.Message = ""
+		stored.IsDeleted = true
+		if forumID, ok := s.db.forumID(stored.Forum); ok {
+			s.db.forums[forumID].Posts--
+		}
+	}
+
+	return stored.Post, nil
+}
+
+func (s *postStore) GetPostsByThread(ctx context.Context, input models.ThreadGetPosts) (posts []models.Post, err error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	thread := make([]*post, 0, len(s.db.postsByThread[input.ThreadID]))
+	for _, id := range s.db.postsByThread[input.ThreadID] {
+		thread = append(thread, s.db.posts[id])
+	}
+
+	var selected []*post
+	switch input.Sort {
+	case "tree":
+		selected = s.tree(thread, input)
+	case "parent_tree":
+		selected = s.parentTree(thread, input)
+	case "top":
+		selected = s.top(thread, input)
+	default:
+		selected = s.flat(thread, input)
+	}
+
+	posts = make([]models.Post, 0, len(selected))
+	for _, p := range selected {
+		posts = append(posts, p.Post)
+	}
+
+	return
+}
+
+func (s *postStore) flat(thread []*post, input models.ThreadGetPosts) []*post {
+	sort.Slice(thread, func(i, j int) bool {
+		a, b := thread[i], thread[j]
+		if input.Desc {
+			a, b = b, a
+		}
+		if !a.Created.Equal(b.Created) {
+			return a.Created.Before(b.Created)
+		}
+		return a.ID < b.ID
+	})
+
+	selected := make([]*post, 0)
+	for _, p := range thread {
+		if input.Since > 0 && (!input.Desc && p.ID <= input.Since || input.Desc && p.ID >= input.Since) {
+			continue
+		}
+		if input.HideDeleted && p.IsDeleted {
+			continue
+		}
+		selected = append(selected, p)
+	}
+
+	return limitPosts(selected, input.Limit)
+}
+
+func (s *postStore) tree(thread []*post, input models.ThreadGetPosts) []*post {
+	sortByPath(thread, input.Desc)
+
+	var since *post
+	if input.Since > 0 {
+		var ok bool
+		if since, ok = s.db.posts[input.Since]; !ok {
+			return []*post{}
+		}
+	}
+
+	selected := make([]*post, 0)
+	for _, p := range thread {
+		if since != nil {
+			cmp := comparePaths(p.path, since.path)
+			if !input.Desc && cmp <= 0 || input.Desc && cmp >= 0 {
+				continue
+			}
+		}
+		if input.HideDeleted && p.IsDeleted {
+			continue
+		}
+		selected = append(selected, p)
+	}
+
+	return limitPosts(selected, input.Limit)
+}
+
+func (s *postStore) parentTree(thread []*post, input models.ThreadGetPosts) []*post {
+	roots := make([]*post, 0)
+	children := make(map[int][]*post)
+	for _, p := range thread {
+		if p.Parent == 0 {
+			roots = append(roots, p)
+		}
+		children[p.path[0]] = append(children[p.path[0]], p)
+	}
+	sortByPath(roots, input.Desc)
+
+	if input.Since > 0 {
+		since, ok := s.db.posts[input.Since]
+		if !ok {
+			return []*post{}
+		}
+		filtered := make([]*post, 0)
+		for _, root := range roots {
+			if !input.Desc && root.path[0] > since.path[0] || input.Desc && root.path[0] < since.path[0] {
+				filtered = append(filtered, root)
+			}
+		}
+		roots = filtered
+	}
+	roots = limitPosts(roots, input.Limit)
+
+	selected := make([]*post, 0)
+	for _, root := range roots {
+		branch := children[root.path[0]]
+		sortByPath(branch, false)
+		for _, p := range branch {
+			if input.HideDeleted && p.IsDeleted {
+				continue
+			}
+			selected = append(selected, p)
+		}
+	}
+
+	return selected
+}
+
+/* depth first, every level by score (best first unless Desc) and then by id, since continues after that post */
+func (s *postStore) top(thread []*post, input models.ThreadGetPosts) []*post {
+	children := make(map[int][]*post)
+	for _, p := range thread {
+		children[p.Parent] = append(children[p.Parent], p)
+	}
+	for _, level := range children {
+		sort.Slice(level, func(i, j int) bool {
+			a, b := level[i], level[j]
+			if a.Votes != b.Votes {
+				return a.Votes > b.Votes != input.Desc
+			}
+			return a.ID < b.ID
+		})
+	}
+
+	ordered := make([]*post, 0, len(thread))
+	var walk func(parent int)
+	walk = func(parent int) {
+		for _, p := range children[parent] {
+			ordered = append(ordered, p)
+			walk(p.ID)
+		}
+	}
+	walk(0)
+
+	if input.Since > 0 {
+		found := false
+		for i, p := range ordered {
+			if p.ID == input.Since {
+				ordered, found = ordered[i+1:], true
+				break
+			}
+		}
+		if !found {
+			return []*post{}
+		}
+	}
+
+	selected := make([]*post, 0)
+	for _, p := range ordered {
+		if input.HideDeleted && p.IsDeleted {
+			continue
+		}
+		selected = append(selected, p)
+	}
+
+	return limitPosts(selected, input.Limit)
+}
+
+func (s *postStore) CheckParentPostThread(ctx context.Context, post int) (thread int, err error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	stored, ok := s.db.posts[post]
+	if !ok {
+		return 0, models.ErrConflict
+	}
+
+	return stored.ThreadID, nil
+}
+
+func sortByPath(posts []*post, desc bool) {
+	sort.Slice(posts, func(i, j int) bool {
+		if desc {
+			return comparePaths(posts[i].path, posts[j].path) > 0
+		}
+		return comparePaths(posts[i].path, posts[j].path) < 0
+	})
+}
+
+/* element-wise comparison, a prefix sorts first, like postgres arrays */
+func comparePaths(a, b []int) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return len(a) - len(b)
+}
+
+func limitPosts(posts []*post, limit int) []*post {
+	if len(posts) > limit {
+		return posts[:limit]
+	}
+	return posts
+}
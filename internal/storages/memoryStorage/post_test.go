@@ -0,0 +1,110 @@
+package memoryStorage
+
+import (
+	"context"
+	"github.com/pringleskate/tp_db_forum/internal/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+/*
+	Thread 1 of forum f holds, in creation order:
+
+	1
+	├── 3
+	│   └── 4
+	└── 6
+	2
+	└── 5
+	7
+*/
+func seedPosts(t *testing.T) (*Database, models.ThreadInput) {
+	t.Helper()
+
+	ctx := context.Background()
+	db := NewDatabase()
+	if _, err := NewUserStorage(db).CreateUser(ctx, models.User{Nickname: "alice", Email: "alice@test"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewForumStorage(db).CreateForum(ctx, models.ForumCreate{Slug: "f", Title: "f", User: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	created, err := NewThreadStorage(db).CreateThread(ctx, models.Thread{Author: "alice", Forum: "f", Title: "t", Message: "m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	thread := models.ThreadInput{ThreadID: created.ID}
+
+	at := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, parent := range []int{0, 0, 1, 3, 2, 1, 0} {
+		_, err = NewPostStorage(db).CreatePosts(ctx, thread, "f", at, []models.PostCreate{{Author: "alice", Message: "m", Parent: parent}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db, thread
+}
+
+func TestGetPostsByThread(t *testing.T) {
+	db, thread := seedPosts(t)
+	posts := NewPostStorage(db)
+
+	tests := []struct {
+		name  string
+		sort  string
+		since int
+		limit int
+		desc  bool
+		want  []int
+	}{
+		{name: "flat", sort: "flat", want: []int{1, 2, 3, 4, 5, 6, 7}},
+		{name: "flat desc", sort: "flat", desc: true, want: []int{7, 6, 5, 4, 3, 2, 1}},
+		{name: "flat since", sort: "flat", since: 3, want: []int{4, 5, 6, 7}},
+		{name: "flat since desc", sort: "flat", since: 3, desc: true, want: []int{2, 1}},
+		{name: "flat limit", sort: "flat", limit: 3, want: []int{1, 2, 3}},
+		{name: "default is flat", want: []int{1, 2, 3, 4, 5, 6, 7}},
+
+		{name: "tree", sort: "tree", want: []int{1, 3, 4, 6, 2, 5, 7}},
+		{name: "tree desc", sort: "tree", desc: true, want: []int{7, 5, 2, 6, 4, 3, 1}},
+		{name: "tree since", sort: "tree", since: 4, want: []int{6, 2, 5, 7}},
+		{name: "tree since desc", sort: "tree", since: 4, desc: true, want: []int{3, 1}},
+		{name: "tree limit", sort: "tree", limit: 2, want: []int{1, 3}},
+		{name: "tree since unknown", sort: "tree", since: 100, want: []int{}},
+
+		{name: "parent_tree", sort: "parent_tree", want: []int{1, 3, 4, 6, 2, 5, 7}},
+		{name: "parent_tree desc", sort: "parent_tree", desc: true, want: []int{7, 2, 5, 1, 3, 4, 6}},
+		{name: "parent_tree since", sort: "parent_tree", since: 2, want: []int{7}},
+		{name: "parent_tree since desc", sort: "parent_tree", since: 2, desc: true, want: []int{1, 3, 4, 6}},
+		{name: "parent_tree since child", sort: "parent_tree", since: 5, want: []int{7}},
+		{name: "parent_tree limit counts roots", sort: "parent_tree", limit: 2, want: []int{1, 3, 4, 6, 2, 5}},
+		{name: "parent_tree limit desc", sort: "parent_tree", limit: 1, desc: true, want: []int{7}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit := tt.limit
+			if limit == 0 {
+				limit = 100
+			}
+			got, err := posts.GetPostsByThread(context.Background(), models.ThreadGetPosts{
+				ThreadInput: thread,
+				Sort:        tt.sort,
+				Since:       tt.since,
+				Limit:       limit,
+				Desc:        tt.desc,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ids := make([]int, 0, len(got))
+			for _, p := range got {
+				ids = append(ids, p.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("got %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package memoryStorage
+
+import (
+	"context"
+	"github.com/pringleskate/tp_db_forum/internal/models"
+	"github.com/pringleskate/tp_db_forum/internal/storages/roleStorage"
+	"sort"
+)
+
+type roleStore struct {
+	db *Database
+}
+
+func NewRoleStorage(db *Database) roleStorage.Storage {
+	return &roleStore{
+		db: db,
+	}
+}
+
+func (s *roleStore) GetRole(ctx context.Context, nickname string) (role string, err error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	id, ok := s.db.userID(nickname)
+	if !ok {
+		return role, models.ErrNotFound.WithMessage("cannot find user")
+	}
+
+	if role, ok = s.db.roles[id]; !ok {
+		role = models.RoleMember
+	}
+	return role, nil
+}
+
+func (s *roleStore) SetRole(ctx context.Context, nickname string, role string) (err error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	id, ok := s.db.userID(nickname)
+	if !ok {
+		return models.ErrNotFound.WithMessage("cannot find user")
+	}
+
+	if role == models.RoleMember {
+		delete(s.db.roles, id)
+	} else {
+		s.db.roles[id] = role
+	}
+	return
+}
+
+func (s *roleStore) GetBanned(ctx context.Context, nicknames []string) (banned []string, err error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	for _, nickname := range nicknames {
+		id, ok := s.db.userID(nickname)
+		if ok && s.db.roles[id] == models.RoleBanned {
+			banned = append(banned, s.db.users[id].Nickname)
+		}
+	}
+	return banned, nil
+}
+
+func (s *roleStore) IsModerator(ctx context.Context, nickname string, forum string) (ok bool, err error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	userID, okUser := s.db.userID(nickname)
+	forumID, okForum := s.db.forumID(forum)
+	if !okUser || !okForum {
+		return false, nil
+	}
+
+	return s.db.moderators[forumID][userID], nil
+}
+
+func (s *roleStore) GrantModerator(ctx context.Context, nickname string, forum string, grantedBy string) (err error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	forumID, userID, err := s.ids(nickname, forum)
+	if err != nil {
+		return err
+	}
+
+	users, ok := s.db.moderators[forumID]
+	if !ok {
+		users = make(map[int]bool)
+		s.db.moderators[forumID] = users
+	}
+	users[userID] = true
+	return
+}
+
+func (s *roleStore) RevokeModerator(ctx context.Context, nickname string, forum string) (err error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	forumID, userID, err := s.ids(nickname, forum)
+	if err != nil {
+		return err
+	}
+
+	delete(s.db.moderators[forumID], userID)
+	return
+}
+
+func (s *roleStore) GetModerators(ctx context.Context, forum string) (nicknames []string, err error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	forumID, ok := s.db.forumID(forum)
+	if !ok {
+		return nil, models.ErrNotFound.WithMessage("cannot find forum")
+	}
+
+	nicknames = make([]string, 0, len(s.db.moderators[forumID]))
+	for userID := range s.db.moderators[forumID] {
+		nicknames = append(nicknames, s.db.users[userID].Nickname)
+	}
+	sort.Slice(nicknames, func(i, j int) bool {
+		return key(nicknames[i]) < key(nicknames[j])
+	})
+	return nicknames, nil
+}
+
+func (s *roleStore) ids(nickname string, forum string) (forumID int, userID int, err error) {
+	forumID, ok := s.db.forumID(forum)
+	if !ok {
+		return 0, 0, models.ErrNotFound.WithMessage("cannot find forum")
+	}
+	userID, ok = s.db.userID(nickname)
+	if !ok {
+		return 0, 0, models.ErrNotFound.WithMessage("cannot find user")
+	}
+	return forumID, userID, nil
+}
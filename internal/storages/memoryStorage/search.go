@@ -0,0 +1,165 @@
+package memoryStorage
+
+import (
+	"context"
+	"github.com/pringleskate/tp_db_forum/internal/models"
+	"github.com/pringleskate/tp_db_forum/internal/storages/searchStorage"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+/* same window the postgres storage asks ts_headline for */
+const snippetWords = 35
+
+type searchStore struct {
+	db *Database
+}
+
+func NewSearchStorage(db *Database) searchStorage.Storage {
+	return &searchStore{
+		db: db,
+	}
+}
+
+/*
+A rough stand-in for the 'simple' text search configuration:
+every query word has to occur, rank grows with the number of occurrences,
+and thread titles weigh more than thread messages.
+*/
+func (s *searchStore) Search(ctx context.Context, input models.SearchInput) (results []models.SearchResult, err error) {
+	query := words(input.Query)
+	results = make([]models.SearchResult, 0)
+	if len(query) == 0 {
+		return results, nil
+	}
+
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	matches := func(forum, author string, created time.Time) bool {
+		if input.Forum != "" && key(forum) != key(input.Forum) {
+			return false
+		}
+		if input.Author != "" && key(author) != key(input.Author) {
+			return false
+		}
+		return input.Since.IsZero() || !created.Before(input.Since)
+	}
+
+	for _, p := range s.db.posts {
+		if p.IsDeleted || !matches(p.Forum, p.Author, p.Created) {
+			continue
+		}
+		score := rank(words(p.Message), query)
+		if score == 0 {
+			continue
+		}
+		post := p.Post
+		results = append(results, models.SearchResult{
+			Kind:     "post",
+			Rank:     score,
+			Snippet:  headline(p.Message, query),
+			PostFull: models.PostFull{Post: &post},
+		})
+	}
+
+	for _, t := range s.db.threads {
+		if !matches(t.Forum, t.Author, t.Created) {
+			continue
+		}
+		title, message := words(t.Title), words(t.Message)
+		if rank(append(append([]string{}, title...), message...), query) == 0 {
+			continue
+		}
+		thread := *t
+		results = append(results, models.SearchResult{
+			Kind:     "thread",
+			Rank:     rank(title, query) + 0.4*rank(message, query),
+			Snippet:  headline(t.Title+" "+t.Message, query),
+			PostFull: models.PostFull{Thread: &thread},
+		})
+	}
+
+	sortResults(results)
+	return searchStorage.Merge(results, input.Limit), nil
+}
+
+/* map iteration order is random, fix it before Merge keeps ties stable: posts first, then by ID */
+func sortResults(results []models.SearchResult) {
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if (a.Post == nil) != (b.Post == nil) {
+			return a.Post != nil
+		}
+		if a.Post != nil {
+			return a.Post.ID < b.Post.ID
+		}
+		return a.Thread.ID < b.Thread.ID
+	})
+}
+
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func rank(text []string, query []string) float32 {
+	if len(text) == 0 {
+		return 0
+	}
+
+	total := 0
+	for _, q := range query {
+		count := 0
+		for _, w := range text {
+			if w == q {
+				count++
+			}
+		}
+		if count == 0 {
+			return 0
+		}
+		total += count
+	}
+
+	return float32(total) / float32(len(text))
+}
+
+func headline(text string, query []string) string {
+	fields := strings.Fields(text)
+	first := -1
+	for i, field := range fields {
+		for _, w := range words(field) {
+			if contains(query, w) {
+				fields[i] = "<b>" + field + "</b>"
+				if first < 0 {
+					first = i
+				}
+				break
+			}
+		}
+	}
+
+	start := first - 5
+	if start < 0 {
+		start = 0
+	}
+	end := start + snippetWords
+	if end > len(fields) {
+		end = len(fields)
+	}
+
+	return strings.Join(fields[start:end], " ")
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
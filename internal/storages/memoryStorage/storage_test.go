@@ -0,0 +1,159 @@
+package memoryStorage
+
+import (
+	"context"
+	"errors"
+	"github.com/pringleskate/tp_db_forum/internal/models"
+	"testing"
+	"time"
+)
+
+/* the kinds the handlers turn into 404 and 409, the same ones the postgres storages return */
+func TestErrorKinds(t *testing.T) {
+	db, thread := seedPosts(t)
+	ctx := context.Background()
+	users := NewUserStorage(db)
+	forums := NewForumStorage(db)
+	threads := NewThreadStorage(db)
+	posts := NewPostStorage(db)
+	votes := NewVoteStorage(db)
+	service := NewDatabaseService(db)
+
+	other, err := threads.CreateThread(ctx, models.Thread{Author: "alice", Forum: "f", Title: "t", Message: "m", Slug: "other"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		call func() error
+		want error
+	}{
+		{"unknown user profile", func() error {
+			_, err := users.GetProfile(ctx, "nobody")
+			return err
+		}, models.ErrNotFound},
+		{"unknown user for post", func() error {
+			return users.GetUserForPost(ctx, "nobody", &models.User{})
+		}, models.ErrNotFound},
+		{"taken nickname", func() error {
+			_, err := users.CreateUser(ctx, models.User{Nickname: "ALICE", Email: "other@test"}, nil)
+			return err
+		}, models.ErrConflict},
+		{"taken email", func() error {
+			_, err := users.CreateUser(ctx, models.User{Nickname: "bob", Email: "Alice@test"}, nil)
+			return err
+		}, models.ErrConflict},
+
+		{"unknown forum", func() error {
+			_, err := forums.GetDetails(ctx, models.ForumInput{Slug: "nowhere"})
+			return err
+		}, models.ErrNotFound},
+		{"unknown forum for post", func() error {
+			return forums.GetForumForPost(ctx, "nowhere", &models.Forum{})
+		}, models.ErrNotFound},
+		{"forum of unknown user", func() error {
+			_, err := forums.CreateForum(ctx, models.ForumCreate{Slug: "g", Title: "g", User: "nobody"})
+			return err
+		}, models.ErrNotFound},
+		{"taken forum slug", func() error {
+			_, err := forums.CreateForum(ctx, models.ForumCreate{Slug: "F", Title: "f", User: "alice"})
+			return err
+		}, models.ErrConflict},
+
+		{"unknown thread", func() error {
+			_, err := threads.GetDetails(ctx, models.ThreadInput{ThreadID: 100})
+			return err
+		}, models.ErrNotFound},
+		{"unknown thread slug", func() error {
+			_, err := threads.GetDetails(ctx, models.ThreadInput{Slug: "nowhere"})
+			return err
+		}, models.ErrNotFound},
+		{"unknown thread for post", func() error {
+			return threads.GetThreadForPost(ctx, models.ThreadInput{ThreadID: 100}, &models.Thread{})
+		}, models.ErrNotFound},
+		{"thread in unknown forum", func() error {
+			_, err := threads.CreateThread(ctx, models.Thread{Author: "alice", Forum: "nowhere", Title: "t", Message: "m"})
+			return err
+		}, models.ErrNotFound},
+		{"taken thread slug", func() error {
+			_, err := threads.CreateThread(ctx, models.Thread{Author: "alice", Forum: "f", Title: "t", Message: "m", Slug: "OTHER"})
+			return err
+		}, models.ErrConflict},
+
+		{"unknown post", func() error {
+			return posts.GetPostDetails(ctx, models.PostInput{ID: 100}, &models.Post{})
+		}, models.ErrNotFound},
+		{"post in unknown thread", func() error {
+			_, err := posts.CreatePosts(ctx, models.ThreadInput{ThreadID: 100}, "f", time.Now(), []models.PostCreate{{Author: "alice", Message: "m"}})
+			return err
+		}, models.ErrNotFound},
+		{"post by unknown user", func() error {
+			_, err := posts.CreatePosts(ctx, thread, "f", time.Now(), []models.PostCreate{{Author: "nobody", Message: "m"}})
+			return err
+		}, models.ErrNotFound},
+		{"parent in another thread", func() error {
+			_, err := posts.CreatePosts(ctx, models.ThreadInput{ThreadID: other.ID}, "f", time.Now(), []models.PostCreate{{Author: "alice", Message: "m", Parent: 1}})
+			return err
+		}, models.ErrConflict},
+		{"unknown parent", func() error {
+			_, err := posts.CreatePosts(ctx, thread, "f", time.Now(), []models.PostCreate{{Author: "alice", Message: "m", Parent: 100}})
+			return err
+		}, models.ErrConflict},
+
+		{"vote on unknown thread", func() error {
+			_, err := votes.CreateVote(ctx, models.Vote{User: "alice", Voice: 1, Thread: models.ThreadInput{ThreadID: 100}})
+			return err
+		}, models.ErrNotFound},
+		{"vote by unknown user", func() error {
+			_, err := votes.CreateVote(ctx, models.Vote{User: "nobody", Voice: 1, Thread: thread})
+			return err
+		}, models.ErrNotFound},
+		{"vote on unknown post", func() error {
+			_, err := votes.CreatePostVote(ctx, models.Vote{User: "alice", Voice: 1, Post: 100})
+			return err
+		}, models.ErrNotFound},
+
+		{"clear unknown forum", func() error {
+			return service.ClearForum(ctx, "nowhere")
+		}, models.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if !errors.Is(err, tt.want) {
+				t.Errorf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+/* archived threads and deleted posts refuse changes with 409 */
+func TestConflictsOnClosedContent(t *testing.T) {
+	db, thread := seedPosts(t)
+	ctx := context.Background()
+	threads := NewThreadStorage(db)
+	posts := NewPostStorage(db)
+	votes := NewVoteStorage(db)
+
+	if _, err := posts.DeletePost(ctx, models.PostInput{ID: 7}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := votes.CreatePostVote(ctx, models.Vote{User: "alice", Voice: 1, Post: 7}); !errors.Is(err, models.ErrConflict) {
+		t.Errorf("vote on deleted post: got %v, want %v", err, models.ErrConflict)
+	}
+
+	if _, err := threads.ArchiveThread(ctx, thread); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := posts.CreatePosts(ctx, thread, "f", time.Now(), []models.PostCreate{{Author: "alice", Message: "m"}}); !errors.Is(err, models.ErrConflict) {
+		t.Errorf("post in archived thread: got %v, want %v", err, models.ErrConflict)
+	}
+	if _, err := votes.CreateVote(ctx, models.Vote{User: "alice", Voice: 1, Thread: thread}); !errors.Is(err, models.ErrConflict) {
+		t.Errorf("vote on archived thread: got %v, want %v", err, models.ErrConflict)
+	}
+	if _, err := posts.DeletePost(ctx, models.PostInput{ID: 1}); !errors.Is(err, models.ErrConflict) {
+		t.Errorf("delete in archived thread: got %v, want %v", err, models.ErrConflict)
+	}
+}
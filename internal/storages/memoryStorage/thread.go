@@ -0,0 +1,231 @@
+package memoryStorage
+
+import (
+	"context"
+	"github.com/pringleskate/tp_db_forum/internal/models"
+	"github.com/pringleskate/tp_db_forum/internal/storages/threadStorage"
+	"sort"
+	"time"
+)
+
+type threadStore struct {
+	db *Database
+}
+
+func NewThreadStorage(db *Database) threadStorage.Storage {
+	return &threadStore{
+		db: db,
+	}
+}
+
+func (s *threadStore) CreateThread(ctx context.Context, input models.Thread) (thread models.Thread, err error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	userID, ok := s.db.userID(input.Author)
+	if !ok {
+		return thread, models.ErrNotFound
+	}
+	forumID, ok := s.db.forumID(input.Forum)
+	if !ok {
+		return thread, models.ErrNotFound
+	}
+	if input.Slug != "" {
+		if _, ok := s.db.threadsBySlug[key(input.Slug)]; ok {
+			return thread, models.ErrConflict
+		}
+	}
+
+	id := s.db.nextThreadID
+	s.db.nextThreadID++
+
+	thread = input
+	thread.ID = id
+	thread.Author = s.db.users[userID].Nickname
+	thread.Forum = s.db.forums[forumID].Slug
+
+	stored := thread
+	s.db.threads[id] = &stored
+	if thread.Slug != "" {
+		s.db.threadsBySlug[key(thread.Slug)] = id
+	}
+
+	s.db.forums[forumID].Threads++
+	s.db.forums[forumID].touch(thread.Created)
+	s.db.addUserToForum(forumID, userID)
+
+	return thread, nil
+}
+
+func (s *threadStore) GetDetails(ctx context.Context, input models.ThreadInput) (thread models.Thread, err error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	stored, ok := s.db.thread(input)
+	if !ok {
+		return thread, models.ErrNotFound
+	}
+
+	return *stored, nil
+}
+
+func (s *threadStore) UpdateThread(ctx context.Context, input models.ThreadUpdate) (thread models.Thread, err error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	stored, ok := s.db.thread(input.ThreadInput)
+	if !ok {
+		return thread, models.ErrNotFound
+	}
+
+	if stored.IsArchived && (input.Title != "" || input.Message != "") {
+		return thread, models.ErrConflict.WithCode("thread_archived").WithMessage("thread is archived")
+	}
+
+	if input.Title != "" {
+		stored.Title = input.Title
+	}
+	if input.Message != "" {
+		stored.Message = input.Message
+	}
+
+	return *stored, nil
+}
+
+func (s *threadStore) GetThreadsByForum(ctx context.Context, input models.ForumGetThreads) (threads []models.Thread, err error) {
+	var since time.Time
+	if input.Since != "" {
+		since, err = time.Parse(time.RFC3339Nano, input.Since)
+		if err != nil {
+			return threads, models.ErrInternal.Wrap(err)
+		}
+	}
+
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	threads = make([]models.Thread, 0)
+	for _, thread := range s.db.threads {
+		if key(thread.Forum) != key(input.Slug) {
+			continue
+		}
+		if thread.IsArchived && !input.Archived {
+			continue
+		}
+		if input.Since != "" {
+			if !input.Desc && thread.Created.Before(since) {
+				continue
+			}
+			if input.Desc && thread.Created.After(since) {
+				continue
+			}
+		}
+		threads = append(threads, *thread)
+	}
+
+	sort.Slice(threads, func(i, j int) bool {
+		a, b := threads[i], threads[j]
+		if input.Desc {
+			a, b = b, a
+		}
+		if !a.Created.Equal(b.Created) {
+			return a.Created.Before(b.Created)
+		}
+		return a.ID < b.ID
+	})
+
+	if len(threads) > input.Limit {
+		threads = threads[:input.Limit]
+	}
+
+	return
+}
+
+func (s *threadStore) CheckThreadIfExists(ctx context.Context, input models.ThreadInput) (thread models.ThreadInput, err error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	stored, ok := s.db.thread(input)
+	if !ok {
+		return thread, models.ErrNotFound
+	}
+
+	thread.ThreadID = stored.ID
+	return
+}
+
+func (s *threadStore) GetThreadForPost(ctx context.Context, input models.ThreadInput, thread *models.Thread) (err error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	stored, ok := s.db.threads[input.ThreadID]
+	if !ok {
+		return models.ErrNotFound
+	}
+
+	*thread = *stored
+	return
+}
+
+func (s *threadStore) GetForumByThread(ctx context.Context, input *models.ThreadInput) (forum string, err error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	stored, ok := s.db.thread(*input)
+	if !ok {
+		return forum, models.ErrNotFound
+	}
+
+	input.ThreadID = stored.ID
+	return stored.Forum, nil
+}
+
+func (s *threadStore) DeleteThread(ctx context.Context, input models.ThreadInput) (thread models.Thread, err error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	stored, ok := s.db.thread(input)
+	if !ok {
+		return thread, models.ErrNotFound
+	}
+
+	for vote := range s.db.votes {
+		if vote.thread == stored.ID {
+			delete(s.db.votes, vote)
+		}
+	}
+
+	posts := 0
+	for _, id := range s.db.postsByThread[stored.ID] {
+		if !s.db.posts[id].IsDeleted {
+			posts++
+		}
+		s.db.dropPost(id)
+	}
+	delete(s.db.postsByThread, stored.ID)
+
+	delete(s.db.threads, stored.ID)
+	if stored.Slug != "" {
+		delete(s.db.threadsBySlug, key(stored.Slug))
+	}
+
+	if forumID, ok := s.db.forumID(stored.Forum); ok {
+		s.db.forums[forumID].Threads--
+		s.db.forums[forumID].Posts -= posts
+	}
+
+	return *stored, nil
+}
+
+func (s *threadStore) ArchiveThread(ctx context.Context, input models.ThreadInput) (thread models.Thread, err error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	stored, ok := s.db.thread(input)
+	if !ok {
+		return thread, models.ErrNotFound
+	}
+
+	stored.IsArchived = true
+	return *stored, nil
+}
@@ -0,0 +1,145 @@
+package memoryStorage
+
+import (
+	"context"
+	"github.com/pringleskate/tp_db_forum/internal/models"
+	"github.com/pringleskate/tp_db_forum/internal/storages/userStorage"
+	"sort"
+)
+
+type userStore struct {
+	db *Database
+}
+
+func NewUserStorage(db *Database) userStorage.Storage {
+	return &userStore{
+		db: db,
+	}
+}
+
+func (s *userStore) CreateUser(ctx context.Context, input models.User, passwordHash []byte) (user models.User, err error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if _, ok := s.db.usersByNick[key(input.Nickname)]; ok {
+		return user, models.ErrConflict.WithMessage("conflict user")
+	}
+	if _, ok := s.db.usersByEmail[key(input.Email)]; ok {
+		return user, models.ErrConflict.WithMessage("conflict user")
+	}
+
+	id := s.db.nextUserID
+	s.db.nextUserID++
+
+	user = input
+	stored := user
+	s.db.users[id] = &stored
+	s.db.usersByNick[key(input.Nickname)] = id
+	s.db.usersByEmail[key(input.Email)] = id
+	if passwordHash != nil {
+		s.db.passwords[id] = passwordHash
+	}
+
+	return
+}
+
+func (s *userStore) GetProfile(ctx context.Context, input string) (user models.User, err error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	id, ok := s.db.userID(input)
+	if !ok {
+		return user, models.ErrNotFound
+	}
+
+	return *s.db.users[id], nil
+}
+
+func (s *userStore) UpdateProfile(ctx context.Context, input models.User) (user models.User, err error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	id, ok := s.db.userID(input.Nickname)
+	if !ok {
+		return user, models.ErrNotFound
+	}
+	stored := s.db.users[id]
+
+	if input.Email != "" {
+		if owner, ok := s.db.usersByEmail[key(input.Email)]; ok && owner != id {
+			return user, models.ErrConflict
+		}
+		delete(s.db.usersByEmail, key(stored.Email))
+		stored.Email = input.Email
+		s.db.usersByEmail[key(stored.Email)] = id
+	}
+	if input.Fullname != "" {
+		stored.Fullname = input.Fullname
+	}
+	if input.About != "" {
+		stored.About = input.About
+	}
+
+	return *stored, nil
+}
+
+func (s *userStore) GetUsers(ctx context.Context, input models.ForumGetUsers, forumID int) (users []models.User, err error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	users = make([]models.User, 0)
+	for userID := range s.db.forumUsers[forumID] {
+		user := *s.db.users[userID]
+		if input.Since != "" {
+			if !input.Desc && key(user.Nickname) <= key(input.Since) {
+				continue
+			}
+			if input.Desc && key(user.Nickname) >= key(input.Since) {
+				continue
+			}
+		}
+		users = append(users, user)
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		if input.Desc {
+			return key(users[i].Nickname) > key(users[j].Nickname)
+		}
+		return key(users[i].Nickname) < key(users[j].Nickname)
+	})
+
+	if len(users) > input.Limit {
+		users = users[:input.Limit]
+	}
+
+	return
+}
+
+func (s *userStore) GetUserForPost(ctx context.Context, input string, user *models.User) (err error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	id, ok := s.db.userID(input)
+	if !ok {
+		return models.ErrNotFound
+	}
+
+	stored := s.db.users[id]
+	user.Nickname = input
+	user.Fullname = stored.Fullname
+	user.Email = stored.Email
+	user.About = stored.About
+	return
+}
+
+func (s *userStore) GetEmailConflictUser(ctx context.Context, email string) (user models.User, err error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	id, ok := s.db.usersByEmail[key(email)]
+	if !ok {
+		return user, models.ErrNotFound
+	}
+
+	return *s.db.users[id], nil
+}
@@ -0,0 +1,182 @@
+package memoryStorage
+
+import (
+	"context"
+	"github.com/pringleskate/tp_db_forum/internal/models"
+	"github.com/pringleskate/tp_db_forum/internal/storages/voteStorage"
+	"sort"
+)
+
+type voteStore struct {
+	db *Database
+}
+
+func NewVoteStorage(db *Database) voteStorage.Storage {
+	return &voteStore{
+		db: db,
+	}
+}
+
+/* the score moves by what the vote changes: 1 for a new one, 2 for a flipped one, 0 for a repeated one */
+func (s *voteStore) CreateVote(ctx context.Context, vote models.Vote) (thread models.Thread, err error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if _, ok := s.db.userID(vote.User); !ok {
+		return thread, models.ErrNotFound.WithMessage("cannot find user")
+	}
+	stored, ok := s.db.threads[vote.Thread.ThreadID]
+	if !ok {
+		return thread, models.ErrNotFound
+	}
+	if stored.IsArchived {
+		return thread, models.ErrConflict.WithCode("thread_archived").WithMessage("thread is archived")
+	}
+
+	cast := voteKey{nickname: key(vote.User), thread: stored.ID}
+	voice := vote.Voice == 1
+	if old, ok := s.db.votes[cast]; ok {
+		stored.Votes -= voiceValue(old)
+	}
+	s.db.votes[cast] = voice
+	stored.Votes += voiceValue(voice)
+
+	return *stored, nil
+}
+
+func (s *voteStore) CreatePostVote(ctx context.Context, vote models.Vote) (post models.Post, err error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	stored, ok := s.db.posts[vote.Post]
+	if !ok {
+		return post, models.ErrNotFound
+	}
+	if s.db.threads[stored.ThreadID].IsArchived {
+		return post, models.ErrConflict.WithCode("thread_archived").WithMessage("thread is archived")
+	}
+	if stored.IsDeleted {
+		return post, models.ErrConflict.WithMessage("post is deleted")
+	}
+	if _, ok := s.db.userID(vote.User); !ok {
+		return post, models.ErrNotFound.WithMessage("cannot find user")
+	}
+
+	if s.db.postVotes[stored.ID] == nil {
+		s.db.postVotes[stored.ID] = make(map[string]bool)
+	}
+	voice := vote.Voice == 1
+	if old, ok := s.db.postVotes[stored.ID][key(vote.User)]; ok {
+		stored.Votes -= voiceValue(old)
+	}
+	s.db.postVotes[stored.ID][key(vote.User)] = voice
+	stored.Votes += voiceValue(voice)
+
+	return stored.Post, nil
+}
+
+func (s *voteStore) DeleteVote(ctx context.Context, vote models.Vote) (thread models.Thread, err error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	stored, ok := s.db.threads[vote.Thread.ThreadID]
+	if !ok {
+		return thread, models.ErrNotFound
+	}
+	if stored.IsArchived {
+		return thread, models.ErrConflict.WithCode("thread_archived").WithMessage("thread is archived")
+	}
+
+	cast := voteKey{nickname: key(vote.User), thread: stored.ID}
+	if voice, ok := s.db.votes[cast]; ok {
+		delete(s.db.votes, cast)
+		stored.Votes -= voiceValue(voice)
+	}
+
+	return *stored, nil
+}
+
+func (s *voteStore) DeletePostVote(ctx context.Context, vote models.Vote) (post models.Post, err error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	stored, ok := s.db.posts[vote.Post]
+	if !ok {
+		return post, models.ErrNotFound
+	}
+	if s.db.threads[stored.ThreadID].IsArchived {
+		return post, models.ErrConflict.WithCode("thread_archived").WithMessage("thread is archived")
+	}
+
+	if voice, ok := s.db.postVotes[stored.ID][key(vote.User)]; ok {
+		delete(s.db.postVotes[stored.ID], key(vote.User))
+		stored.Votes -= voiceValue(voice)
+	}
+
+	return stored.Post, nil
+}
+
+func (s *voteStore) GetThreadVotes(ctx context.Context, input models.ThreadGetVotes) (votes []models.VoteRecord, err error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	votes = make([]models.VoteRecord, 0)
+	for vote, voice := range s.db.votes {
+		if vote.thread != input.ThreadID {
+			continue
+		}
+		if input.Since != "" && (!input.Desc && vote.nickname <= key(input.Since) || input.Desc && vote.nickname >= key(input.Since)) {
+			continue
+		}
+		votes = append(votes, s.record(vote, voice))
+	}
+
+	sort.Slice(votes, func(i, j int) bool {
+		return key(votes[i].User) < key(votes[j].User) != input.Desc
+	})
+	return limitVotes(votes, input.Limit), nil
+}
+
+func (s *voteStore) GetUserVotes(ctx context.Context, input models.UserGetVotes) (votes []models.VoteRecord, err error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	votes = make([]models.VoteRecord, 0)
+	for vote, voice := range s.db.votes {
+		if vote.nickname != key(input.Nickname) {
+			continue
+		}
+		if input.Since != 0 && (!input.Desc && vote.thread <= input.Since || input.Desc && vote.thread >= input.Since) {
+			continue
+		}
+		votes = append(votes, s.record(vote, voice))
+	}
+
+	sort.Slice(votes, func(i, j int) bool {
+		return votes[i].Thread < votes[j].Thread != input.Desc
+	})
+	return limitVotes(votes, input.Limit), nil
+}
+
+/* votes are keyed by the lower-cased nickname, the listing shows it as the user spelled it */
+func (s *voteStore) record(vote voteKey, voice bool) models.VoteRecord {
+	nickname := vote.nickname
+	if id, ok := s.db.userID(vote.nickname); ok {
+		nickname = s.db.users[id].Nickname
+	}
+	return models.VoteRecord{User: nickname, Thread: vote.thread, Voice: voiceValue(voice)}
+}
+
+func voiceValue(voice bool) int {
+	if voice {
+		return 1
+	}
+	return -1
+}
+
+func limitVotes(votes []models.VoteRecord, limit int) []models.VoteRecord {
+	if len(votes) > limit {
+		return votes[:limit]
+	}
+	return votes
+}
@@ -0,0 +1,42 @@
+package memoryStorage
+
+import (
+	"context"
+	"github.com/pringleskate/tp_db_forum/internal/models"
+	"github.com/pringleskate/tp_db_forum/internal/storages/voteStorage/votetest"
+	"testing"
+)
+
+func TestConcurrentVotes(t *testing.T) {
+	db, thread := seedPosts(t)
+	users := []string{"alice"}
+	for _, nickname := range []string{"bob", "carol", "dave", "eve", "frank", "grace", "heidi"} {
+		if _, err := NewUserStorage(db).CreateUser(context.Background(), models.User{Nickname: nickname, Email: nickname + "@test"}, nil); err != nil {
+			t.Fatal(err)
+		}
+		users = append(users, nickname)
+	}
+
+	votetest.Hammer(t, NewVoteStorage(db), thread, 1, users)
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	threadSum := 0
+	for vote, voice := range db.votes {
+		if vote.thread == thread.ThreadID {
+			threadSum += voiceValue(voice)
+		}
+	}
+	if got := db.threads[thread.ThreadID].Votes; got != threadSum {
+		t.Errorf("thread votes %d, sum over votes %d", got, threadSum)
+	}
+
+	postSum := 0
+	for _, voice := range db.postVotes[1] {
+		postSum += voiceValue(voice)
+	}
+	if got := db.posts[1].Votes; got != postSum {
+		t.Errorf("post votes %d, sum over post votes %d", got, postSum)
+	}
+}
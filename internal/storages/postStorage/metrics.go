@@ -0,0 +1,61 @@
+package postStorage
+
+import (
+	"context"
+	"github.com/pringleskate/tp_db_forum/internal/metrics"
+	"github.com/pringleskate/tp_db_forum/internal/models"
+	"time"
+)
+
+/* times every call of the wrapped post storage, postgres or memory alike */
+type measured struct {
+	storage Storage
+	queries *metrics.Queries
+}
+
+func WithMetrics(storage Storage, queries *metrics.Queries) Storage {
+	return &measured{
+		storage: storage,
+		queries: queries,
+	}
+}
+
+func (m *measured) CreatePosts(ctx context.Context, thread models.ThreadInput, forum string, created time.Time, posts []models.PostCreate) (post []models.Post, err error) {
+	defer m.queries.Observe("post", "CreatePosts", time.Now(), &err)
+	return m.storage.CreatePosts(ctx, thread, forum, created, posts)
+}
+
+func (m *measured) CreatePost(ctx context.Context, input models.Post) (post models.Post, err error) {
+	defer m.queries.Observe("post", "CreatePost", time.Now(), &err)
+	return m.storage.CreatePost(ctx, input)
+}
+
+func (m *measured) GetPostDetails(ctx context.Context, input models.PostInput, post *models.Post) (err error) {
+	defer m.queries.Observe("post", "GetPostDetails", time.Now(), &err)
+	return m.storage.GetPostDetails(ctx, input, post)
+}
+
+func (m *measured) UpdatePost(ctx context.Context, input models.PostUpdate, editor string) (post models.Post, err error) {
+	defer m.queries.Observe("post", "UpdatePost", time.Now(), &err)
+	return m.storage.UpdatePost(ctx, input, editor)
+}
+
+func (m *measured) GetPostRevisions(ctx context.Context, id int) (revisions []models.PostRevision, err error) {
+	defer m.queries.Observe("post", "GetPostRevisions", time.Now(), &err)
+	return m.storage.GetPostRevisions(ctx, id)
+}
+
+func (m *measured) DeletePost(ctx context.Context, input models.PostInput) (post models.Post, err error) {
+	defer m.queries.Observe("post", "DeletePost", time.Now(), &err)
+	return m.storage.DeletePost(ctx, input)
+}
+
+func (m *measured) GetPostsByThread(ctx context.Context, input models.ThreadGetPosts) (posts []models.Post, err error) {
+	defer m.queries.Observe("post", "GetPostsByThread", time.Now(), &err)
+	return m.storage.GetPostsByThread(ctx, input)
+}
+
+func (m *measured) CheckParentPostThread(ctx context.Context, post int) (thread int, err error) {
+	defer m.queries.Observe("post", "CheckParentPostThread", time.Now(), &err)
+	return m.storage.CheckParentPostThread(ctx, post)
+}
@@ -1,22 +1,25 @@
 package postStorage
 
 import (
-	"errors"
+	"context"
 	"fmt"
 	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx"
+	"github.com/pringleskate/tp_db_forum/internal/logger"
 	"github.com/pringleskate/tp_db_forum/internal/models"
-	"strconv"
 	"strings"
+	"time"
 )
 
 type Storage interface {
-	CreatePosts(thread models.ThreadInput, forum string, created string, posts []models.PostCreate) (post []models.Post, err error)
-	CreatePost(input models.Post) (post models.Post, err error)
-	GetPostDetails(input models.PostInput, post *models.Post) (err error)
-	UpdatePost(input models.PostUpdate) (post models.Post, err error)
-	GetPostsByThread(input models.ThreadGetPosts) (posts []models.Post, err error)
-	CheckParentPostThread(post int) (thread int, err error)
+	CreatePosts(ctx context.Context, thread models.ThreadInput, forum string, created time.Time, posts []models.PostCreate) (post []models.Post, err error)
+	CreatePost(ctx context.Context, input models.Post) (post models.Post, err error)
+	GetPostDetails(ctx context.Context, input models.PostInput, post *models.Post) (err error)
+	UpdatePost(ctx context.Context, input models.PostUpdate, editor string) (post models.Post, err error)
+	GetPostRevisions(ctx context.Context, id int) (revisions []models.PostRevision, err error)
+	DeletePost(ctx context.Context, input models.PostInput) (post models.Post, err error)
+	GetPostsByThread(ctx context.Context, input models.ThreadGetPosts) (posts []models.Post, err error)
+	CheckParentPostThread(ctx context.Context, post int) (thread int, err error)
 }
 
 type storage struct {
@@ -29,130 +32,254 @@ func NewStorage(db *pgx.ConnPool) Storage {
 	}
 }
 
-func (s storage) CreatePosts(thread models.ThreadInput, forum string, created string, posts []models.PostCreate) (post []models.Post, err error) {
-	sqlStr := "INSERT INTO posts(id, parent, thread, forum, author, created, message, path) VALUES "
-	vals := []interface{}{}
-	for _, post := range posts {
-		var authorID int
-		err = s.db.QueryRow(`SELECT id FROM users WHERE nickname = $1`,
-			post.Author,
-		).Scan(&authorID)
-		if err != nil {
-			fmt.Println("cannot find user", post.Author, err)
-			return nil, models.Error{Code: "404", Message: "cannot find user"}
+const selectThreadArchived = `
+	SELECT archived
+	FROM threads
+	WHERE id = $1
+	FOR SHARE
+`
+
+const selectPostAuthors = `
+	SELECT id, nickname
+	FROM users
+	WHERE nickname = ANY($1::text[]::citext[])
+`
+
+const selectParentThreads = `
+	SELECT id, thread
+	FROM posts
+	WHERE id = ANY($1::integer[])
+`
+
+const selectPostIDs = `
+	SELECT nextval('post_id_seq')::integer
+	FROM generate_series(1, $1)
+`
+
+const insertPosts = `
+	INSERT INTO posts (id, parent, thread, forum, author, created, message, path)
+	SELECT u.id, u.parent, $5, $6, u.author, $7, u.message, COALESCE(p.path, ARRAY[]::integer[]) || u.id
+	FROM unnest($1::integer[], $2::integer[], $3::text[], $4::text[]) AS u(id, parent, author, message)
+	LEFT JOIN posts p ON p.id = u.parent
+`
+
+const insertForumUsers = `
+	INSERT INTO forum_users (forumID, userID)
+	SELECT $1, unnest($2::integer[])
+	ON CONFLICT DO NOTHING
+`
+
+func (s storage) CreatePosts(ctx context.Context, thread models.ThreadInput, forum string, created time.Time, posts []models.PostCreate) (post []models.Post, err error) {
+	post = make([]models.Post, 0, len(posts))
+	if len(posts) == 0 {
+		return post, nil
+	}
+
+	tx, err := s.db.BeginEx(ctx, nil)
+	if err != nil {
+		return nil, models.ErrInternal.Wrap(err)
+	}
+	defer tx.Rollback()
+
+	/* FOR SHARE keeps the thread from being archived or deleted until the posts are in */
+	var archived bool
+	err = tx.QueryRowEx(ctx, selectThreadArchived, nil, thread.ThreadID).Scan(&archived)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, models.ErrNotFound.WithMessage("cannot find thread")
 		}
+		return nil, models.ErrInternal.Wrap(err)
+	}
+	if archived {
+		return nil, models.ErrConflict.WithCode("thread_archived").WithMessage("thread is archived")
+	}
 
-		var forumID int
-		err = s.db.QueryRow(`SELECT id FROM forums WHERE slug = $1`,
-			forum,
-		).Scan(&forumID)
-		if err != nil {
-			fmt.Println("cannot find forumID", forum)
-			return nil, models.Error{Code: "404", Message: "cannot find thread"}
+	authors := make([]string, 0, len(posts))
+	parents := make([]int32, 0, len(posts))
+	messages := make([]string, 0, len(posts))
+	for _, p := range posts {
+		authors = append(authors, p.Author)
+		parents = append(parents, int32(p.Parent))
+		messages = append(messages, p.Message)
+	}
+
+	authorIDs, err := s.resolveAuthors(ctx, tx, authors)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.checkParents(ctx, tx, thread.ThreadID, parents)
+	if err != nil {
+		return nil, err
+	}
 
-		//	return nil, errors.New("404")
+	var forumID int
+	err = tx.QueryRowEx(ctx, "UPDATE forums SET posts = posts + $2, activity = greatest(activity, $3) WHERE slug = $1 RETURNING ID", nil, forum, len(posts), created).Scan(&forumID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, models.ErrNotFound.WithMessage("cannot find forum")
 		}
+		logger.FromContext(ctx).Error("query failed", "op", "postStorage.CreatePosts", "err", err)
+		return nil, models.ErrInternal.Wrap(err)
+	}
 
-		sqlQuery := `
-		INSERT INTO forum_users (forumID, userID)
-		VALUES ($1,$2)`
-		_, err = s.db.Exec(sqlQuery, forumID, authorID)
-		if err != nil {
-			if pqErr, ok := err.(pgx.PgError); ok {
-				if pqErr.Code != pgerrcode.UniqueViolation {
-					return nil, errors.New("500")
-				}
-			}
+	ids, err := s.nextPostIDs(ctx, tx, len(posts))
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecEx(ctx, insertPosts, nil, ids, parents, authors, messages, thread.ThreadID, forum, created)
+	if err != nil {
+		if pqErr, ok := err.(pgx.PgError); ok && pqErr.Code == pgerrcode.ForeignKeyViolation {
+			return nil, models.ErrNotFound.WithMessage("cannot find user")
 		}
+		logger.FromContext(ctx).Error("query failed", "op", "postStorage.CreatePosts", "err", err)
+		return nil, models.ErrInternal.Wrap(err)
+	}
 
-		if post.Parent == 0 {
-			sqlStr += "(nextval('post_id_seq'::regclass), ?, ?, ?, ?, ?, ?, " +
-				"ARRAY[currval(pg_get_serial_sequence('posts', 'id'))::INTEGER]),"
-			vals = append(vals, post.Parent, thread.ThreadID, forum, post.Author, created, post.Message)
-		} else {
-			var parentThreadId int32
-			err = s.db.QueryRow("SELECT thread FROM posts WHERE id = $1",
-				post.Parent,
-			).Scan(&parentThreadId)
-			if err != nil {
-				return nil, models.Error{Code: "409", Message: "Parent post was created in another thread"}
-
-			/*	fmt.Println("cannot find thread by post", post.Parent)
-				return nil, err*/
-			}
-			if parentThreadId != int32(thread.ThreadID) {
-				return nil, models.Error{Code: "409", Message: "Parent post was created in another thread"}
-			}
+	_, err = tx.ExecEx(ctx, insertForumUsers, nil, forumID, authorIDs)
+	if err != nil {
+		logger.FromContext(ctx).Error("query failed", "op", "postStorage.CreatePosts", "err", err)
+		return nil, models.ErrInternal.Wrap(err)
+	}
+
+	if err = tx.CommitEx(ctx); err != nil {
+		logger.FromContext(ctx).Error("query failed", "op", "postStorage.CreatePosts", "err", err)
+		return nil, models.ErrInternal.Wrap(err)
+	}
+
+	for i, p := range posts {
+		post = append(post, models.Post{
+			ThreadInput: models.ThreadInput{ThreadID: thread.ThreadID},
+			ID:          int(ids[i]),
+			Parent:      p.Parent,
+			Author:      p.Author,
+			Message:     p.Message,
+			Forum:       forum,
+			Created:     created,
+		})
+	}
 
-			sqlStr += " (nextval('post_id_seq'::regclass), ?, ?, ?, ?, ?, ?, " +
-				"(SELECT posts.path FROM posts WHERE posts.id = ? AND posts.thread = ?) || " +
-				"currval(pg_get_serial_sequence('posts', 'id'))::INTEGER),"
+	return post, nil
+}
+
+/* returns distinct user IDs of the authors, 404 if any of them does not exist */
+func (s storage) resolveAuthors(ctx context.Context, tx *pgx.Tx, authors []string) (ids []int32, err error) {
+	rows, err := tx.QueryEx(ctx, selectPostAuthors, nil, authors)
+	if err != nil {
+		logger.FromContext(ctx).Error("query failed", "op", "postStorage.resolveAuthors", "err", err)
+		return nil, models.ErrInternal.Wrap(err)
+	}
+	defer rows.Close()
 
-			vals = append(vals, post.Parent, thread.ThreadID, forum, post.Author, created, post.Message, post.Parent, thread.ThreadID)
+	found := make(map[string]bool, len(authors))
+	for rows.Next() {
+		var id int32
+		var nickname string
+		if err = rows.Scan(&id, &nickname); err != nil {
+			return nil, models.ErrInternal.Wrap(err)
 		}
+		found[strings.ToLower(nickname)] = true
+		ids = append(ids, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, models.ErrInternal.Wrap(err)
+	}
 
+	for _, author := range authors {
+		if !found[strings.ToLower(author)] {
+			return nil, models.ErrNotFound.WithMessage("cannot find user")
+		}
 	}
-	sqlStr = strings.TrimSuffix(sqlStr, ",")
 
-	sqlStr += " RETURNING id, parent, thread, forum, author, created, message, edited "
+	return ids, nil
+}
 
-	sqlStr = ReplaceSQL(sqlStr, "?")
-	if len(posts) > 0 {
-		rows, err := s.db.Query(sqlStr, vals...)
-		if err != nil {
-			fmt.Println(err)
-			return nil, err
+/* every non-root parent has to exist and belong to the same thread */
+func (s storage) checkParents(ctx context.Context, tx *pgx.Tx, thread int, parents []int32) (err error) {
+	wanted := make([]int32, 0)
+	for _, parent := range parents {
+		if parent != 0 {
+			wanted = append(wanted, parent)
 		}
-		scanPost := models.Post{}
-		for rows.Next() {
-			err := rows.Scan(
-				&scanPost.ID,
-				&scanPost.Parent,
-				&scanPost.ThreadID,
-				&scanPost.Forum,
-				&scanPost.Author,
-				&scanPost.Created,
-				&scanPost.Message,
-				&scanPost.IsEdited,
-			)
-			if err != nil {
-				rows.Close()
-				fmt.Println(err)
-				return nil, err
-			}
-			post = append(post, scanPost)
+	}
+	if len(wanted) == 0 {
+		return nil
+	}
+
+	rows, err := tx.QueryEx(ctx, selectParentThreads, nil, wanted)
+	if err != nil {
+		logger.FromContext(ctx).Error("query failed", "op", "postStorage.checkParents", "err", err)
+		return models.ErrInternal.Wrap(err)
+	}
+	defer rows.Close()
+
+	threads := make(map[int32]int, len(wanted))
+	for rows.Next() {
+		var id int32
+		var parentThread int
+		if err = rows.Scan(&id, &parentThread); err != nil {
+			return models.ErrInternal.Wrap(err)
 		}
-		rows.Close()
+		threads[id] = parentThread
 	}
-	return post, nil
+	if err = rows.Err(); err != nil {
+		return models.ErrInternal.Wrap(err)
+	}
+
+	for _, parent := range wanted {
+		if parentThread, ok := threads[parent]; !ok || parentThread != thread {
+			return models.ErrConflict.WithMessage("Parent post was created in another thread")
+		}
+	}
+
+	return nil
 }
 
-func ReplaceSQL(old, searchPattern string) string {
-	tmpCount := strings.Count(old, searchPattern)
-	for m := 1; m <= tmpCount; m++ {
-		old = strings.Replace(old, searchPattern, "$"+strconv.Itoa(m), 1)
+func (s storage) nextPostIDs(ctx context.Context, tx *pgx.Tx, count int) (ids []int32, err error) {
+	rows, err := tx.QueryEx(ctx, selectPostIDs, nil, count)
+	if err != nil {
+		logger.FromContext(ctx).Error("query failed", "op", "postStorage.nextPostIDs", "err", err)
+		return nil, models.ErrInternal.Wrap(err)
+	}
+	defer rows.Close()
+
+	ids = make([]int32, 0, count)
+	for rows.Next() {
+		var id int32
+		if err = rows.Scan(&id); err != nil {
+			return nil, models.ErrInternal.Wrap(err)
+		}
+		ids = append(ids, id)
 	}
-	return old
+	if err = rows.Err(); err != nil {
+		return nil, models.ErrInternal.Wrap(err)
+	}
+	if len(ids) != count {
+		return nil, models.ErrInternal.Wrap(fmt.Errorf("post_id_seq gave %d ids, expected %d", len(ids), count))
+	}
+
+	return ids, nil
 }
 
-func (s *storage) CreatePost(input models.Post) (post models.Post, err error) {
+func (s *storage) CreatePost(ctx context.Context, input models.Post) (post models.Post, err error) {
 	if input.Parent == 0 {
-		err = s.db.QueryRow("INSERT INTO posts (author, created, forum, message, parent, thread, path) VALUES ($1,$2,$3,$4,$5,$6, array[(select currval('post_id_seq')::integer)]) RETURNING ID",
+		err = s.db.QueryRowEx(ctx, "INSERT INTO posts (author, created, forum, message, parent, thread, path) VALUES ($1,$2,$3,$4,$5,$6, array[(select currval('post_id_seq')::integer)]) RETURNING ID", nil,
 			input.Author, input.Created, input.Forum, input.Message, input.Parent, input.ThreadInput.ThreadID).Scan(&post.ID)
 	} else {
-		err = s.db.QueryRow("INSERT INTO posts (author, created, forum, message, parent, thread, path) VALUES ($1,$2,$3,$4,$5,$6, (SELECT path FROM posts WHERE id = $5) || (select currval('post_id_seq')::integer)) RETURNING ID",
+		err = s.db.QueryRowEx(ctx, "INSERT INTO posts (author, created, forum, message, parent, thread, path) VALUES ($1,$2,$3,$4,$5,$6, (SELECT path FROM posts WHERE id = $5) || (select currval('post_id_seq')::integer)) RETURNING ID", nil,
 			input.Author, input.Created, input.Forum, input.Message, input.Parent, input.ThreadInput.ThreadID).Scan(&post.ID)
 	}
 
 	if pqErr, ok := err.(pgx.PgError); ok {
-		fmt.Println(err)
 		switch pqErr.Code {
 		case pgerrcode.UniqueViolation:
-			return post, models.Error{Code: "409", Message: "conflict post"}
+			return post, models.ErrConflict.WithMessage("conflict post")
 		case pgerrcode.NotNullViolation, pgerrcode.ForeignKeyViolation:
-			return post, models.Error{Code: "404", Message: "conflict post"}
+			return post, models.ErrNotFound.WithMessage("conflict post")
 		default:
-			return post, models.Error{Code: "500", Message: "conflict post"}
+			logger.FromContext(ctx).Error("query failed", "op", "postStorage.CreatePost", "err", err)
+			return post, models.ErrInternal.WithMessage("conflict post").Wrap(err)
 		}
 	}
 
@@ -167,105 +294,233 @@ func (s *storage) CreatePost(input models.Post) (post models.Post, err error) {
 	return
 }
 
-func (s *storage) GetPostDetails(input models.PostInput, post *models.Post) (err error) {
-	err = s.db.QueryRow("SELECT author, created, forum, message, ID , edited, parent, thread FROM posts WHERE ID = $1", input.ID).
-				Scan(&post.Author, &post.Created, &post.Forum, &post.Message, &post.ID, &post.IsEdited, &post.Parent, &post.ThreadInput.ThreadID)
+func (s *storage) GetPostDetails(ctx context.Context, input models.PostInput, post *models.Post) (err error) {
+	err = s.db.QueryRowEx(ctx, "SELECT author, created, forum, message, ID , edited, deleted, parent, thread, votes FROM posts WHERE ID = $1", nil, input.ID).
+				Scan(&post.Author, &post.Created, &post.Forum, &post.Message, &post.ID, &post.IsEdited, &post.IsDeleted, &post.Parent, &post.ThreadInput.ThreadID, &post.Votes)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return models.Error{Code: "404"}
+			return models.ErrNotFound
 
 		}
-		return models.Error{Code: "500"}
+		return models.ErrInternal.Wrap(err)
 	}
 	return
 }
 
-func (s *storage) UpdatePost(input models.PostUpdate) (post models.Post, err error) {
+const insertFirstRevision = `
+	INSERT INTO post_revisions (postID, revision, message, editor, created)
+	SELECT ID, 1, message, author, created
+	FROM posts
+	WHERE ID = $1
+	ON CONFLICT DO NOTHING
+`
+
+const insertNextRevision = `
+	INSERT INTO post_revisions (postID, revision, message, editor)
+	SELECT $1, MAX(revision) + 1, $2, $3
+	FROM post_revisions
+	WHERE postID = $1
+`
+
+const selectRevisions = `
+	SELECT revision, message, editor, created
+	FROM post_revisions
+	WHERE postID = $1
+	ORDER BY revision
+`
+
+/*
+	A change of the message is kept in post_revisions. The text the post was created with
+	becomes revision 1 on its first edit, so posts nobody edited cost no rows.
+*/
+func (s *storage) UpdatePost(ctx context.Context, input models.PostUpdate, editor string) (post models.Post, err error) {
+	tx, err := s.db.BeginEx(ctx, nil)
+	if err != nil {
+		return post, models.ErrInternal.Wrap(err)
+	}
+	defer tx.Rollback()
+
 	var oldMessage string
-	err = s.db.QueryRow("SELECT message FROM posts WHERE ID = $1", input.ID).
-		Scan(&oldMessage)
+	var deleted, archived bool
+	err = tx.QueryRowEx(ctx, "SELECT p.message, p.deleted, t.archived FROM posts p JOIN threads t ON t.ID = p.thread WHERE p.ID = $1 FOR UPDATE OF p", nil, input.ID).
+		Scan(&oldMessage, &deleted, &archived)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return post, models.Error{Code: "404"}
+			return post, models.ErrNotFound
 		}
-		return post, models.Error{Code: "500"}
+		return post, models.ErrInternal.Wrap(err)
+	}
+
+	if deleted {
+		return post, models.ErrConflict.WithMessage("post is deleted")
+	}
+	if archived {
+		return post, models.ErrConflict.WithCode("thread_archived").WithMessage("thread is archived")
 	}
 
 	if input.Message != "" && input.Message != oldMessage {
-		err = s.db.QueryRow("UPDATE posts SET message = $1, edited = $2 WHERE ID = $3 RETURNING author, created, forum, message, ID , edited, parent, thread", input.Message, true, input.ID).
-			Scan(&post.Author, &post.Created, &post.Forum, &post.Message, &post.ID, &post.IsEdited, &post.Parent, &post.ThreadInput.ThreadID)
+		if _, err = tx.ExecEx(ctx, insertFirstRevision, nil, input.ID); err != nil {
+			return post, models.ErrInternal.Wrap(err)
+		}
+		err = tx.QueryRowEx(ctx, "UPDATE posts SET message = $1, edited = $2 WHERE ID = $3 RETURNING author, created, forum, message, ID , edited, parent, thread, votes", nil, input.Message, true, input.ID).
+			Scan(&post.Author, &post.Created, &post.Forum, &post.Message, &post.ID, &post.IsEdited, &post.Parent, &post.ThreadInput.ThreadID, &post.Votes)
+		if err != nil {
+			return post, models.ErrInternal.Wrap(err)
+		}
+		if _, err = tx.ExecEx(ctx, insertNextRevision, nil, input.ID, input.Message, editor); err != nil {
+			return post, models.ErrInternal.Wrap(err)
+		}
 	} else {
-		err = s.db.QueryRow("SELECT author, created, forum, message, ID , edited, parent, thread FROM posts WHERE ID = $1", input.ID).
-			Scan(&post.Author, &post.Created, &post.Forum, &post.Message, &post.ID, &post.IsEdited, &post.Parent, &post.ThreadInput.ThreadID)
+		err = tx.QueryRowEx(ctx, "SELECT author, created, forum, message, ID , edited, parent, thread, votes FROM posts WHERE ID = $1", nil, input.ID).
+			Scan(&post.Author, &post.Created, &post.Forum, &post.Message, &post.ID, &post.IsEdited, &post.Parent, &post.ThreadInput.ThreadID, &post.Votes)
+		if err != nil {
+			return post, models.ErrInternal.Wrap(err)
+		}
+	}
+
+	if err = tx.CommitEx(ctx); err != nil {
+		return post, models.ErrInternal.Wrap(err)
+	}
+	return
+}
+
+/* empty for a post that was never edited, the caller knows its only version */
+func (s *storage) GetPostRevisions(ctx context.Context, id int) (revisions []models.PostRevision, err error) {
+	rows, err := s.db.QueryEx(ctx, selectRevisions, nil, id)
+	if err != nil {
+		return nil, models.ErrInternal.Wrap(err)
+	}
+	defer rows.Close()
+
+	revisions = make([]models.PostRevision, 0)
+	for rows.Next() {
+		revision := models.PostRevision{}
+		if err = rows.Scan(&revision.Revision, &revision.Message, &revision.Editor, &revision.Created); err != nil {
+			return nil, models.ErrInternal.Wrap(err)
+		}
+		revisions = append(revisions, revision)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, models.ErrInternal.Wrap(err)
+	}
+
+	return revisions, nil
+}
+
+/*
+	Soft delete: the row and its path stay so replies keep their place in tree sorts,
+	only the message is blanked. Deleting a tombstone again changes nothing.
+*/
+func (s *storage) DeletePost(ctx context.Context, input models.PostInput) (post models.Post, err error) {
+	tx, err := s.db.BeginEx(ctx, nil)
+	if err != nil {
+		return post, models.ErrInternal.Wrap(err)
+	}
+	defer tx.Rollback()
+
+	var archived bool
+	err = tx.QueryRowEx(ctx, "SELECT p.deleted, t.archived FROM posts p JOIN threads t ON t.ID = p.thread WHERE p.ID = $1 FOR UPDATE", nil, input.ID).
+		Scan(&post.IsDeleted, &archived)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return post, models.ErrNotFound
+		}
+		return post, models.ErrInternal.Wrap(err)
+	}
+
+	if archived {
+		return post, models.ErrConflict.WithCode("thread_archived").WithMessage("thread is archived")
+	}
+
+	if !post.IsDeleted {
+		err = tx.QueryRowEx(ctx, "UPDATE posts SET message = '', deleted = true WHERE ID = $1 RETURNING forum", nil, input.ID).Scan(&post.Forum)
+		if err != nil {
+			logger.FromContext(ctx).Error("query failed", "op", "postStorage.DeletePost", "err", err)
+			return post, models.ErrInternal.Wrap(err)
 		}
 
+		_, err = tx.ExecEx(ctx, "UPDATE forums SET posts = posts - 1 WHERE slug = $1", nil, post.Forum)
+		if err != nil {
+			logger.FromContext(ctx).Error("query failed", "op", "postStorage.DeletePost", "err", err)
+			return post, models.ErrInternal.Wrap(err)
+		}
+	}
+
+	err = tx.QueryRowEx(ctx, "SELECT author, created, forum, message, ID , edited, deleted, parent, thread, votes FROM posts WHERE ID = $1", nil, input.ID).
+		Scan(&post.Author, &post.Created, &post.Forum, &post.Message, &post.ID, &post.IsEdited, &post.IsDeleted, &post.Parent, &post.ThreadInput.ThreadID, &post.Votes)
 	if err != nil {
-		return post, models.Error{Code: "500"}
+		return post, models.ErrInternal.Wrap(err)
+	}
+
+	if err = tx.CommitEx(ctx); err != nil {
+		logger.FromContext(ctx).Error("query failed", "op", "postStorage.DeletePost", "err", err)
+		return post, models.ErrInternal.Wrap(err)
 	}
+
 	return
 }
 
 const selectPostsFlatLimitByID = `
-	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum
+	SELECT p.id, p.author, p.created, p.edited, p.deleted, p.message, p.parent, p.thread, p.forum, p.votes
 	FROM posts p
-	WHERE p.thread = $1
+	WHERE p.thread = $1 AND NOT ($3 AND p.deleted)
 	ORDER BY p.created, p.id
 	LIMIT $2
 `
 
 const selectPostsFlatLimitDescByID = `
-	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum
+	SELECT p.id, p.author, p.created, p.edited, p.deleted, p.message, p.parent, p.thread, p.forum, p.votes
 	FROM posts p
-	WHERE p.thread = $1
+	WHERE p.thread = $1 AND NOT ($3 AND p.deleted)
 	ORDER BY p.created DESC, p.id DESC
 	LIMIT $2
 `
 const selectPostsFlatLimitSinceByID = `
-	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum
+	SELECT p.id, p.author, p.created, p.edited, p.deleted, p.message, p.parent, p.thread, p.forum, p.votes
 	FROM posts p
-	WHERE p.thread = $1 and p.id > $2
+	WHERE p.thread = $1 AND NOT ($4 AND p.deleted) and p.id > $2
 	ORDER BY p.created, p.id
 	LIMIT $3
 `
 const selectPostsFlatLimitSinceDescByID = `
-	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum
+	SELECT p.id, p.author, p.created, p.edited, p.deleted, p.message, p.parent, p.thread, p.forum, p.votes
 	FROM posts p
-	WHERE p.thread = $1 and p.id < $2
+	WHERE p.thread = $1 AND NOT ($4 AND p.deleted) and p.id < $2
 	ORDER BY p.created DESC, p.id DESC
 	LIMIT $3
 `
 const selectPostsTreeLimitByID = `
-	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum
+	SELECT p.id, p.author, p.created, p.edited, p.deleted, p.message, p.parent, p.thread, p.forum, p.votes
 	FROM posts p
-	WHERE p.thread = $1
+	WHERE p.thread = $1 AND NOT ($3 AND p.deleted)
 	ORDER BY p.path
 	LIMIT $2
 `
 const selectPostsTreeLimitDescByID = `
-	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum
+	SELECT p.id, p.author, p.created, p.edited, p.deleted, p.message, p.parent, p.thread, p.forum, p.votes
 	FROM posts p
-	WHERE p.thread = $1
+	WHERE p.thread = $1 AND NOT ($3 AND p.deleted)
 	ORDER BY path DESC
 	LIMIT $2
 `
 const selectPostsTreeLimitSinceByID = `
-	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum
+	SELECT p.id, p.author, p.created, p.edited, p.deleted, p.message, p.parent, p.thread, p.forum, p.votes
 	FROM posts p
-	WHERE p.thread = $1 and (p.path > (SELECT p2.path from posts p2 where p2.id = $2))
+	WHERE p.thread = $1 AND NOT ($4 AND p.deleted) and (p.path > (SELECT p2.path from posts p2 where p2.id = $2))
 	ORDER BY p.path
 	LIMIT $3
 `
 const selectPostsTreeLimitSinceDescByID = `
-	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum
+	SELECT p.id, p.author, p.created, p.edited, p.deleted, p.message, p.parent, p.thread, p.forum, p.votes
 	FROM posts p
-	WHERE p.thread = $1 and (p.path < (SELECT p2.path from posts p2 where p2.id = $2))
+	WHERE p.thread = $1 AND NOT ($4 AND p.deleted) and (p.path < (SELECT p2.path from posts p2 where p2.id = $2))
 	ORDER BY p.path DESC
 	LIMIT $3
 `
 const selectPostsParentTreeLimitByID = `
-	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum
+	SELECT p.id, p.author, p.created, p.edited, p.deleted, p.message, p.parent, p.thread, p.forum, p.votes
 	FROM posts p
-	WHERE p.thread = $1 and p.path[1] IN (
+	WHERE p.thread = $1 AND NOT ($4 AND p.deleted) and p.path[1] IN (
 		SELECT p2.path[1]
 		FROM posts p2
 		WHERE p2.thread = $2 AND p2.parent = 0
@@ -275,9 +530,9 @@ const selectPostsParentTreeLimitByID = `
 	ORDER BY path
 `
 const selectPostsParentTreeLimitDescByID = `
-	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum
+	SELECT p.id, p.author, p.created, p.edited, p.deleted, p.message, p.parent, p.thread, p.forum, p.votes
 	FROM posts p
-	WHERE p.thread = $1 and p.path[1] IN (
+	WHERE p.thread = $1 AND NOT ($4 AND p.deleted) and p.path[1] IN (
 		SELECT p2.path[1]
 		FROM posts p2
 		WHERE p2.parent = 0 and p2.thread = $2
@@ -288,9 +543,9 @@ const selectPostsParentTreeLimitDescByID = `
 `
 
 const selectPostsParentTreeLimitSinceByID = `
-	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum
+	SELECT p.id, p.author, p.created, p.edited, p.deleted, p.message, p.parent, p.thread, p.forum, p.votes
 	FROM posts p
-	WHERE p.thread = $1 and p.path[1] IN (
+	WHERE p.thread = $1 AND NOT ($5 AND p.deleted) and p.path[1] IN (
 		SELECT p2.path[1]
 		FROM posts p2
 		WHERE p2.thread = $2 AND p2.parent = 0 and p2.path[1] > (SELECT p3.path[1] from posts p3 where p3.id = $3)
@@ -300,9 +555,9 @@ const selectPostsParentTreeLimitSinceByID = `
 	ORDER BY p.path
 `
 const selectPostsParentTreeLimitSinceDescByID = `
-	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum
+	SELECT p.id, p.author, p.created, p.edited, p.deleted, p.message, p.parent, p.thread, p.forum, p.votes
 	FROM posts p
-	WHERE p.thread = $1 and p.path[1] IN (
+	WHERE p.thread = $1 AND NOT ($5 AND p.deleted) and p.path[1] IN (
 		SELECT p2.path[1]
 		FROM posts p2
 		WHERE p2.thread = $2 AND p2.parent = 0 and p2.path[1] < (SELECT p3.path[1] from posts p3 where p3.id = $3)
@@ -312,108 +567,141 @@ const selectPostsParentTreeLimitSinceDescByID = `
 	ORDER BY p.path[1] DESC, p.path[2:]
 `
 
-func (s *storage) GetPostsByThread(input models.ThreadGetPosts) (posts []models.Post, err error){
+/*
+	top: every level of the tree ordered by score, best first ($4 = -1) or worst first ($4 = 1),
+	ties by id. The rank of a post is its parent's rank with its own (score, id) appended,
+	so ordering by it walks the tree depth first. since continues after the given post.
+*/
+const selectPostsTop = `
+	WITH RECURSIVE ranked AS (
+		SELECT p.id, ARRAY[$4::integer * p.votes, p.id] AS rank
+		FROM posts p
+		WHERE p.thread = $1 AND p.parent = 0
+		UNION ALL
+		SELECT c.id, r.rank || ARRAY[$4::integer * c.votes, c.id]
+		FROM posts c
+		JOIN ranked r ON c.parent = r.id
+	)
+	SELECT p.id, p.author, p.created, p.edited, p.deleted, p.message, p.parent, p.thread, p.forum, p.votes
+	FROM ranked r
+	JOIN posts p ON p.id = r.id
+	WHERE NOT ($3 AND p.deleted) AND ($5 = 0 OR r.rank > (SELECT rank FROM ranked WHERE id = $5))
+	ORDER BY r.rank
+	LIMIT $2
+`
+
+func (s *storage) GetPostsByThread(ctx context.Context, input models.ThreadGetPosts) (posts []models.Post, err error){
 	var rows *pgx.Rows
 	posts  = make([]models.Post, 0)
 	switch input.Sort {
 	case "flat":
 		if input.Since > 0 {
 			if input.Desc {
-				rows, err = s.db.Query(selectPostsFlatLimitSinceDescByID, input.ThreadInput.ThreadID,
-					input.Since, input.Limit)
+				rows, err = s.db.QueryEx(ctx, selectPostsFlatLimitSinceDescByID, nil, input.ThreadInput.ThreadID,
+					input.Since, input.Limit, input.HideDeleted)
 			} else {
-				rows, err = s.db.Query(selectPostsFlatLimitSinceByID, input.ThreadInput.ThreadID,
-					input.Since, input.Limit)
+				rows, err = s.db.QueryEx(ctx, selectPostsFlatLimitSinceByID, nil, input.ThreadInput.ThreadID,
+					input.Since, input.Limit, input.HideDeleted)
 			}
 		} else {
 			if input.Desc == true {
-				rows, err = s.db.Query(selectPostsFlatLimitDescByID, input.ThreadInput.ThreadID, input.Limit)
+				rows, err = s.db.QueryEx(ctx, selectPostsFlatLimitDescByID, nil, input.ThreadInput.ThreadID, input.Limit, input.HideDeleted)
 			} else {
-				rows, err = s.db.Query(selectPostsFlatLimitByID, input.ThreadInput.ThreadID, input.Limit)
+				rows, err = s.db.QueryEx(ctx, selectPostsFlatLimitByID, nil, input.ThreadInput.ThreadID, input.Limit, input.HideDeleted)
 			}
 		}
 	case "tree":
 		if input.Since > 0 {
 			if input.Desc {
-				rows, err = s.db.Query(selectPostsTreeLimitSinceDescByID, input.ThreadInput.ThreadID,
-					input.Since, input.Limit)
+				rows, err = s.db.QueryEx(ctx, selectPostsTreeLimitSinceDescByID, nil, input.ThreadInput.ThreadID,
+					input.Since, input.Limit, input.HideDeleted)
 			} else {
-				rows, err = s.db.Query(selectPostsTreeLimitSinceByID, input.ThreadInput.ThreadID,
-					input.Since, input.Limit)
+				rows, err = s.db.QueryEx(ctx, selectPostsTreeLimitSinceByID, nil, input.ThreadInput.ThreadID,
+					input.Since, input.Limit, input.HideDeleted)
 			}
 		} else {
 			if input.Desc {
-				rows, err = s.db.Query(selectPostsTreeLimitDescByID, input.ThreadInput.ThreadID, input.Limit)
+				rows, err = s.db.QueryEx(ctx, selectPostsTreeLimitDescByID, nil, input.ThreadInput.ThreadID, input.Limit, input.HideDeleted)
 			} else {
-				rows, err = s.db.Query(selectPostsTreeLimitByID, input.ThreadInput.ThreadID, input.Limit)
+				rows, err = s.db.QueryEx(ctx, selectPostsTreeLimitByID, nil, input.ThreadInput.ThreadID, input.Limit, input.HideDeleted)
 			}
 		}
+	case "top":
+		order := -1
+		if input.Desc {
+			order = 1
+		}
+		rows, err = s.db.QueryEx(ctx, selectPostsTop, nil, input.ThreadInput.ThreadID, input.Limit, input.HideDeleted, order, input.Since)
 	case "parent_tree":
 		if input.Since > 0 {
 			if input.Desc {
-				rows, err = s.db.Query(selectPostsParentTreeLimitSinceDescByID, input.ThreadInput.ThreadID, input.ThreadInput.ThreadID,
-					input.Since, input.Limit)
+				rows, err = s.db.QueryEx(ctx, selectPostsParentTreeLimitSinceDescByID, nil, input.ThreadInput.ThreadID, input.ThreadInput.ThreadID,
+					input.Since, input.Limit, input.HideDeleted)
 			} else {
-				rows, err = s.db.Query(selectPostsParentTreeLimitSinceByID, input.ThreadInput.ThreadID, input.ThreadInput.ThreadID,
-					input.Since, input.Limit)
+				rows, err = s.db.QueryEx(ctx, selectPostsParentTreeLimitSinceByID, nil, input.ThreadInput.ThreadID, input.ThreadInput.ThreadID,
+					input.Since, input.Limit, input.HideDeleted)
 			}
 		} else {
 			if input.Desc {
-				rows, err = s.db.Query(selectPostsParentTreeLimitDescByID, input.ThreadInput.ThreadID, input.ThreadInput.ThreadID,
-					input.Limit)
+				rows, err = s.db.QueryEx(ctx, selectPostsParentTreeLimitDescByID, nil, input.ThreadInput.ThreadID, input.ThreadInput.ThreadID,
+					input.Limit, input.HideDeleted)
 			} else {
-				rows, err = s.db.Query(selectPostsParentTreeLimitByID, input.ThreadInput.ThreadID, input.ThreadInput.ThreadID,
-					input.Limit)
+				rows, err = s.db.QueryEx(ctx, selectPostsParentTreeLimitByID, nil, input.ThreadInput.ThreadID, input.ThreadInput.ThreadID,
+					input.Limit, input.HideDeleted)
 			}
 		}
 	default:
 		if input.Since > 0 {
 			if input.Desc {
-				rows, err = s.db.Query(selectPostsFlatLimitSinceDescByID, input.ThreadInput.ThreadID,
-					input.Since, input.Limit)
+				rows, err = s.db.QueryEx(ctx, selectPostsFlatLimitSinceDescByID, nil, input.ThreadInput.ThreadID,
+					input.Since, input.Limit, input.HideDeleted)
 			} else {
-				rows, err = s.db.Query(selectPostsFlatLimitSinceByID, input.ThreadInput.ThreadID,
-					input.Since, input.Limit)
+				rows, err = s.db.QueryEx(ctx, selectPostsFlatLimitSinceByID, nil, input.ThreadInput.ThreadID,
+					input.Since, input.Limit, input.HideDeleted)
 			}
 		} else {
 			if input.Desc == true {
-				rows, err = s.db.Query(selectPostsFlatLimitDescByID, input.ThreadInput.ThreadID, input.Limit)
+				rows, err = s.db.QueryEx(ctx, selectPostsFlatLimitDescByID, nil, input.ThreadInput.ThreadID, input.Limit, input.HideDeleted)
 			} else {
-				rows, err = s.db.Query(selectPostsFlatLimitByID, input.ThreadInput.ThreadID, input.Limit)
+				rows, err = s.db.QueryEx(ctx, selectPostsFlatLimitByID, nil, input.ThreadInput.ThreadID, input.Limit, input.HideDeleted)
 			}
 		}
 	}
 
 	if err != nil {
-		return posts, models.Error{Code: "500"}
+		return posts, models.ErrInternal.Wrap(err)
 	}
 	defer rows.Close()
 
 	if rows == nil {
-		return posts, models.Error{Code: "500"}
+		return posts, models.ErrInternal.Wrap(err)
 	}
 
 	for rows.Next() {
 		post := models.Post{}
 
-		err = rows.Scan(&post.ID, &post.Author, &post.Created, &post.IsEdited, &post.Message, &post.Parent, &post.ThreadInput.ThreadID, &post.Forum)
+		err = rows.Scan(&post.ID, &post.Author, &post.Created, &post.IsEdited, &post.IsDeleted, &post.Message, &post.Parent, &post.ThreadInput.ThreadID, &post.Forum, &post.Votes)
 		if err != nil {
-			return posts, models.Error{Code: "500"}
+			return posts, models.ErrInternal.Wrap(err)
 		}
 
 		posts = append(posts, post)
 	}
 
+	if err = rows.Err(); err != nil {
+		return posts, models.ErrInternal.Wrap(err)
+	}
+
 	return 
 }
 
-func (s storage) CheckParentPostThread(post int) (thread int, err error) {
-	err = s.db.QueryRow("SELECT thread FROM posts WHERE ID = $1", post).Scan(&thread)
+func (s storage) CheckParentPostThread(ctx context.Context, post int) (thread int, err error) {
+	err = s.db.QueryRowEx(ctx, "SELECT thread FROM posts WHERE ID = $1", nil, post).Scan(&thread)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return 0, models.Error{Code: "409"}
+			return 0, models.ErrConflict
 		}
-		return 0, models.Error{Code: "500"}
+		return 0, models.ErrInternal.Wrap(err)
 	}
 
 	return
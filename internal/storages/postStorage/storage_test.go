@@ -0,0 +1,172 @@
+package postStorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx"
+	"github.com/pringleskate/tp_db_forum/internal/models"
+	"github.com/pringleskate/tp_db_forum/internal/testdb"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+/* ids, paths, the forum counter and forum_users come out as the per-post insert left them, a bad post writes nothing */
+func TestCreatePosts(t *testing.T) {
+	db := testdb.Open(t)
+	thread := models.ThreadInput{ThreadID: testdb.Seed(t, db, "alice", "bob")}
+	s := storage{db: db}
+	ctx := context.Background()
+
+	root, err := s.CreatePosts(ctx, thread, "f", time.Now(), []models.PostCreate{{Author: "alice", Message: "root"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	posts, err := s.CreatePosts(ctx, thread, "f", time.Now(), []models.PostCreate{
+		{Author: "bob", Message: "answer", Parent: root[0].ID},
+		{Author: "alice", Message: "second root"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantPaths := map[int]string{
+		root[0].ID:  fmt.Sprintf("{%d}", root[0].ID),
+		posts[0].ID: fmt.Sprintf("{%d,%d}", root[0].ID, posts[0].ID),
+		posts[1].ID: fmt.Sprintf("{%d}", posts[1].ID),
+	}
+	for id, want := range wantPaths {
+		var path string
+		if err = db.QueryRow("SELECT path::text FROM posts WHERE id = $1", id).Scan(&path); err != nil {
+			t.Fatal(err)
+		}
+		if path != want {
+			t.Errorf("post %d: path %s, want %s", id, path, want)
+		}
+	}
+
+	var other int
+	if err = db.QueryRow("INSERT INTO threads (author, forum, message, title) VALUES ('bob', 'f', 'm', 'other') RETURNING ID").Scan(&other); err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.CreatePosts(ctx, models.ThreadInput{ThreadID: other}, "f", time.Now(), []models.PostCreate{
+		{Author: "alice", Message: "fine"},
+		{Author: "alice", Message: "foreign parent", Parent: root[0].ID},
+	})
+	if !errors.Is(err, models.ErrConflict) {
+		t.Errorf("parent in another thread: got %v, want %v", err, models.ErrConflict)
+	}
+	_, err = s.CreatePosts(ctx, thread, "f", time.Now(), []models.PostCreate{{Author: "alice", Message: "fine"}, {Author: "nobody", Message: "m"}})
+	if !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("unknown author: got %v, want %v", err, models.ErrNotFound)
+	}
+
+	var count, counter, members int
+	err = db.QueryRow("SELECT (SELECT count(*) FROM posts), (SELECT posts FROM forums WHERE slug = 'f'), (SELECT count(*) FROM forum_users)").
+		Scan(&count, &counter, &members)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 || counter != 3 {
+		t.Errorf("%d posts with forums.posts %d, want 3 and 3", count, counter)
+	}
+	if members != 2 {
+		t.Errorf("forum_users has %d rows, want 2", members)
+	}
+}
+
+/*
+	go test -run - -bench CreatePosts ./internal/storages/postStorage with FORUM_TEST_DSN set.
+	Every batch alternates root posts and answers to one existing post, by two authors.
+*/
+func BenchmarkCreatePosts(b *testing.B) {
+	db := testdb.Open(b)
+	thread := models.ThreadInput{ThreadID: testdb.Seed(b, db, "alice", "bob")}
+	s := storage{db: db}
+	ctx := context.Background()
+
+	root, err := s.CreatePosts(ctx, thread, "f", time.Now(), []models.PostCreate{{Author: "alice", Message: "root"}})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, size := range []int{1, 100, 1000} {
+		batch := make([]models.PostCreate, size)
+		for i := range batch {
+			batch[i] = models.PostCreate{Author: []string{"alice", "bob"}[i%2], Message: "message " + strconv.Itoa(i)}
+			if i%2 == 1 {
+				batch[i].Parent = root[0].ID
+			}
+		}
+
+		b.Run(fmt.Sprintf("batch=%d/set", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := s.CreatePosts(ctx, thread, "f", time.Now(), batch); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("batch=%d/per_post", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if err := createPostsPerPost(ctx, db, thread, "f", time.Now(), batch); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+/* the insert CreatePosts replaced: author, forum, forum_users and parent round trips for every post, then one VALUES list */
+func createPostsPerPost(ctx context.Context, db *pgx.ConnPool, thread models.ThreadInput, forum string, created time.Time, posts []models.PostCreate) error {
+	tx, err := db.BeginEx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	values := make([]string, 0, len(posts))
+	args := make([]interface{}, 0, 8*len(posts))
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	for _, post := range posts {
+		var authorID, forumID, parentThread int
+		if err = tx.QueryRowEx(ctx, "SELECT id FROM users WHERE nickname = $1", nil, post.Author).Scan(&authorID); err != nil {
+			return err
+		}
+		if err = tx.QueryRowEx(ctx, "SELECT id FROM forums WHERE slug = $1", nil, forum).Scan(&forumID); err != nil {
+			return err
+		}
+		if _, err = tx.ExecEx(ctx, "INSERT INTO forum_users (forumID, userID) VALUES ($1, $2) ON CONFLICT DO NOTHING", nil, forumID, authorID); err != nil {
+			return err
+		}
+
+		path := "ARRAY[currval('post_id_seq')::integer]"
+		if post.Parent != 0 {
+			if err = tx.QueryRowEx(ctx, "SELECT thread FROM posts WHERE id = $1", nil, post.Parent).Scan(&parentThread); err != nil {
+				return err
+			}
+			if parentThread != thread.ThreadID {
+				return models.ErrConflict
+			}
+			path = "(SELECT path FROM posts WHERE id = " + arg(post.Parent) + ") || currval('post_id_seq')::integer"
+		}
+
+		values = append(values, fmt.Sprintf("(nextval('post_id_seq'), %s, %s, %s, %s, %s, %s, %s)",
+			arg(post.Parent), arg(thread.ThreadID), arg(forum), arg(post.Author), arg(created), arg(post.Message), path))
+	}
+
+	query := "INSERT INTO posts (id, parent, thread, forum, author, created, message, path) VALUES " + strings.Join(values, ", ")
+	if _, err = tx.ExecEx(ctx, query, nil, args...); err != nil {
+		return err
+	}
+	if _, err = tx.ExecEx(ctx, "UPDATE forums SET posts = posts + $2 WHERE slug = $1", nil, forum, len(posts)); err != nil {
+		return err
+	}
+
+	return tx.CommitEx(ctx)
+}
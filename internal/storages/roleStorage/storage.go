@@ -0,0 +1,192 @@
+package roleStorage
+
+import (
+	"context"
+	"github.com/jackc/pgx"
+	"github.com/pringleskate/tp_db_forum/internal/logger"
+	"github.com/pringleskate/tp_db_forum/internal/models"
+)
+
+type Storage interface {
+	GetRole(ctx context.Context, nickname string) (role string, err error)
+	SetRole(ctx context.Context, nickname string, role string) (err error)
+	GetBanned(ctx context.Context, nicknames []string) (banned []string, err error)
+	IsModerator(ctx context.Context, nickname string, forum string) (ok bool, err error)
+	GrantModerator(ctx context.Context, nickname string, forum string, grantedBy string) (err error)
+	RevokeModerator(ctx context.Context, nickname string, forum string) (err error)
+	GetModerators(ctx context.Context, forum string) (nicknames []string, err error)
+}
+
+type storage struct {
+	db *pgx.ConnPool
+}
+
+/* constructor */
+func NewStorage(db *pgx.ConnPool) Storage {
+	return &storage{
+		db: db,
+	}
+}
+
+var (
+	selectRole   = "SELECT COALESCE(r.role, 'member') FROM users u LEFT JOIN user_roles r ON r.userID = u.ID WHERE u.nickname = $1"
+	upsertRole   = "INSERT INTO user_roles (userID, role) VALUES ($1, $2) ON CONFLICT (userID) DO UPDATE SET role = EXCLUDED.role"
+	deleteRole   = "DELETE FROM user_roles WHERE userID = $1"
+	selectBanned = "SELECT u.nickname FROM users u JOIN user_roles r ON r.userID = u.ID WHERE r.role = 'banned' AND u.nickname = ANY($1::text[]::citext[])"
+
+	selectIsModerator = "SELECT EXISTS (SELECT 1 FROM forum_moderators m JOIN users u ON u.ID = m.userID JOIN forums f ON f.ID = m.forumID WHERE u.nickname = $1 AND f.slug = $2)"
+	insertModerator   = "INSERT INTO forum_moderators (forumID, userID, granted_by) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING"
+	deleteModerator   = "DELETE FROM forum_moderators WHERE forumID = $1 AND userID = $2"
+	selectModerators  = "SELECT u.nickname FROM forum_moderators m JOIN users u ON u.ID = m.userID WHERE m.forumID = $1 ORDER BY u.nickname"
+)
+
+func (s *storage) GetRole(ctx context.Context, nickname string) (role string, err error) {
+	err = s.db.QueryRowEx(ctx, selectRole, nil, nickname).Scan(&role)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return role, models.ErrNotFound.WithMessage("cannot find user")
+		}
+		return role, models.ErrInternal.Wrap(err)
+	}
+
+	return
+}
+
+/* RoleMember removes the stored role */
+func (s *storage) SetRole(ctx context.Context, nickname string, role string) (err error) {
+	userID, err := s.userID(ctx, nickname)
+	if err != nil {
+		return err
+	}
+
+	if role == models.RoleMember {
+		_, err = s.db.ExecEx(ctx, deleteRole, nil, userID)
+	} else {
+		_, err = s.db.ExecEx(ctx, upsertRole, nil, userID, role)
+	}
+	if err != nil {
+		logger.FromContext(ctx).Error("query failed", "op", "roleStorage.SetRole", "err", err)
+		return models.ErrInternal.Wrap(err)
+	}
+
+	return
+}
+
+func (s *storage) GetBanned(ctx context.Context, nicknames []string) (banned []string, err error) {
+	rows, err := s.db.QueryEx(ctx, selectBanned, nil, nicknames)
+	if err != nil {
+		logger.FromContext(ctx).Error("query failed", "op", "roleStorage.GetBanned", "err", err)
+		return nil, models.ErrInternal.Wrap(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var nickname string
+		if err = rows.Scan(&nickname); err != nil {
+			return nil, models.ErrInternal.Wrap(err)
+		}
+		banned = append(banned, nickname)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, models.ErrInternal.Wrap(err)
+	}
+	return banned, nil
+}
+
+func (s *storage) IsModerator(ctx context.Context, nickname string, forum string) (ok bool, err error) {
+	err = s.db.QueryRowEx(ctx, selectIsModerator, nil, nickname, forum).Scan(&ok)
+	if err != nil {
+		return false, models.ErrInternal.Wrap(err)
+	}
+
+	return
+}
+
+func (s *storage) GrantModerator(ctx context.Context, nickname string, forum string, grantedBy string) (err error) {
+	forumID, err := s.forumID(ctx, forum)
+	if err != nil {
+		return err
+	}
+	userID, err := s.userID(ctx, nickname)
+	if err != nil {
+		return err
+	}
+
+	if _, err = s.db.ExecEx(ctx, insertModerator, nil, forumID, userID, grantedBy); err != nil {
+		logger.FromContext(ctx).Error("query failed", "op", "roleStorage.GrantModerator", "err", err)
+		return models.ErrInternal.Wrap(err)
+	}
+
+	return
+}
+
+func (s *storage) RevokeModerator(ctx context.Context, nickname string, forum string) (err error) {
+	forumID, err := s.forumID(ctx, forum)
+	if err != nil {
+		return err
+	}
+	userID, err := s.userID(ctx, nickname)
+	if err != nil {
+		return err
+	}
+
+	if _, err = s.db.ExecEx(ctx, deleteModerator, nil, forumID, userID); err != nil {
+		logger.FromContext(ctx).Error("query failed", "op", "roleStorage.RevokeModerator", "err", err)
+		return models.ErrInternal.Wrap(err)
+	}
+
+	return
+}
+
+func (s *storage) GetModerators(ctx context.Context, forum string) (nicknames []string, err error) {
+	forumID, err := s.forumID(ctx, forum)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryEx(ctx, selectModerators, nil, forumID)
+	if err != nil {
+		logger.FromContext(ctx).Error("query failed", "op", "roleStorage.GetModerators", "err", err)
+		return nil, models.ErrInternal.Wrap(err)
+	}
+	defer rows.Close()
+
+	nicknames = make([]string, 0)
+	for rows.Next() {
+		var nickname string
+		if err = rows.Scan(&nickname); err != nil {
+			return nil, models.ErrInternal.Wrap(err)
+		}
+		nicknames = append(nicknames, nickname)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, models.ErrInternal.Wrap(err)
+	}
+	return nicknames, nil
+}
+
+func (s *storage) userID(ctx context.Context, nickname string) (ID int, err error) {
+	err = s.db.QueryRowEx(ctx, "SELECT ID FROM users WHERE nickname = $1", nil, nickname).Scan(&ID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return ID, models.ErrNotFound.WithMessage("cannot find user")
+		}
+		return ID, models.ErrInternal.Wrap(err)
+	}
+
+	return
+}
+
+func (s *storage) forumID(ctx context.Context, slug string) (ID int, err error) {
+	err = s.db.QueryRowEx(ctx, "SELECT ID FROM forums WHERE slug = $1", nil, slug).Scan(&ID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return ID, models.ErrNotFound.WithMessage("cannot find forum")
+		}
+		return ID, models.ErrInternal.Wrap(err)
+	}
+
+	return
+}
@@ -0,0 +1,129 @@
+package searchStorage
+
+import (
+	"context"
+	"database/sql"
+	"github.com/jackc/pgx"
+	"github.com/pringleskate/tp_db_forum/internal/logger"
+	"github.com/pringleskate/tp_db_forum/internal/models"
+	"sort"
+)
+
+type Storage interface {
+	Search(ctx context.Context, input models.SearchInput) (results []models.SearchResult, err error)
+}
+
+type storage struct {
+	db *pgx.ConnPool
+}
+
+/* constructor */
+func NewStorage(db *pgx.ConnPool) Storage {
+	return &storage{
+		db: db,
+	}
+}
+
+/* ts_headline is slow, so it only runs on the rows that survived the LIMIT */
+const selectPostHits = `
+	SELECT p.id, p.author, p.created, p.forum, p.message, p.edited, p.parent, p.thread, hit.rank,
+	       ts_headline('simple', p.message, hit.query, 'StartSel=<b>, StopSel=</b>, MaxWords=35, MinWords=15')
+	FROM (
+		SELECT p.id, ts_rank(p.search, q.query) AS rank, q.query
+		FROM posts p, plainto_tsquery('simple', $1) q(query)
+		WHERE p.search @@ q.query AND NOT p.deleted
+		  AND ($2 = '' OR p.forum = $2::citext)
+		  AND ($3 = '' OR p.author = $3::citext)
+		  AND ($4::timestamptz IS NULL OR p.created >= $4::timestamptz)
+		ORDER BY rank DESC, p.id
+		LIMIT $5
+	) hit
+	JOIN posts p ON p.id = hit.id
+	ORDER BY hit.rank DESC, p.id
+`
+
+const selectThreadHits = `
+	SELECT t.id, t.slug, t.author, t.created, t.forum, t.title, t.message, t.votes, t.archived, hit.rank,
+	       ts_headline('simple', t.title || ' ' || t.message, hit.query, 'StartSel=<b>, StopSel=</b>, MaxWords=35, MinWords=15')
+	FROM (
+		SELECT t.id, ts_rank(t.search, q.query) AS rank, q.query
+		FROM threads t, plainto_tsquery('simple', $1) q(query)
+		WHERE t.search @@ q.query
+		  AND ($2 = '' OR t.forum = $2::citext)
+		  AND ($3 = '' OR t.author = $3::citext)
+		  AND ($4::timestamptz IS NULL OR t.created >= $4::timestamptz)
+		ORDER BY rank DESC, t.id
+		LIMIT $5
+	) hit
+	JOIN threads t ON t.id = hit.id
+	ORDER BY hit.rank DESC, t.id
+`
+
+/* posts and threads are ranked separately, the best Limit of both lists are merged */
+func (s *storage) Search(ctx context.Context, input models.SearchInput) (results []models.SearchResult, err error) {
+	var since interface{}
+	if !input.Since.IsZero() {
+		since = input.Since
+	}
+
+	results = make([]models.SearchResult, 0)
+
+	rows, err := s.db.QueryEx(ctx, selectPostHits, nil, input.Query, input.Forum, input.Author, since, input.Limit)
+	if err != nil {
+		logger.FromContext(ctx).Error("query failed", "op", "searchStorage.Search", "err", err)
+		return results, models.ErrInternal.Wrap(err)
+	}
+	for rows.Next() {
+		post := new(models.Post)
+		result := models.SearchResult{Kind: "post"}
+		err = rows.Scan(&post.ID, &post.Author, &post.Created, &post.Forum, &post.Message, &post.IsEdited, &post.Parent, &post.ThreadInput.ThreadID, &result.Rank, &result.Snippet)
+		if err != nil {
+			rows.Close()
+			return results, models.ErrInternal.Wrap(err)
+		}
+		result.Post = post
+		results = append(results, result)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return results, models.ErrInternal.Wrap(err)
+	}
+
+	rows, err = s.db.QueryEx(ctx, selectThreadHits, nil, input.Query, input.Forum, input.Author, since, input.Limit)
+	if err != nil {
+		logger.FromContext(ctx).Error("query failed", "op", "searchStorage.Search", "err", err)
+		return results, models.ErrInternal.Wrap(err)
+	}
+	for rows.Next() {
+		thread := new(models.Thread)
+		slug := sql.NullString{}
+		result := models.SearchResult{Kind: "thread"}
+		err = rows.Scan(&thread.ID, &slug, &thread.Author, &thread.Created, &thread.Forum, &thread.Title, &thread.Message, &thread.Votes, &thread.IsArchived, &result.Rank, &result.Snippet)
+		if err != nil {
+			rows.Close()
+			return results, models.ErrInternal.Wrap(err)
+		}
+		if slug.Valid {
+			thread.Slug = slug.String
+		}
+		result.Thread = thread
+		results = append(results, result)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return results, models.ErrInternal.Wrap(err)
+	}
+
+	return Merge(results, input.Limit), nil
+}
+
+/* orders hits by rank, stable so equal ranks keep the storage order, and cuts the list to limit */
+func Merge(results []models.SearchResult, limit int) []models.SearchResult {
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Rank > results[j].Rank
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
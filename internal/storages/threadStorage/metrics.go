@@ -0,0 +1,66 @@
+package threadStorage
+
+import (
+	"context"
+	"github.com/pringleskate/tp_db_forum/internal/metrics"
+	"github.com/pringleskate/tp_db_forum/internal/models"
+	"time"
+)
+
+/* times every call of the wrapped thread storage, postgres or memory alike */
+type measured struct {
+	storage Storage
+	queries *metrics.Queries
+}
+
+func WithMetrics(storage Storage, queries *metrics.Queries) Storage {
+	return &measured{
+		storage: storage,
+		queries: queries,
+	}
+}
+
+func (m *measured) CreateThread(ctx context.Context, input models.Thread) (thread models.Thread, err error) {
+	defer m.queries.Observe("thread", "CreateThread", time.Now(), &err)
+	return m.storage.CreateThread(ctx, input)
+}
+
+func (m *measured) GetDetails(ctx context.Context, input models.ThreadInput) (thread models.Thread, err error) {
+	defer m.queries.Observe("thread", "GetDetails", time.Now(), &err)
+	return m.storage.GetDetails(ctx, input)
+}
+
+func (m *measured) UpdateThread(ctx context.Context, input models.ThreadUpdate) (thread models.Thread, err error) {
+	defer m.queries.Observe("thread", "UpdateThread", time.Now(), &err)
+	return m.storage.UpdateThread(ctx, input)
+}
+
+func (m *measured) GetThreadsByForum(ctx context.Context, input models.ForumGetThreads) (threads []models.Thread, err error) {
+	defer m.queries.Observe("thread", "GetThreadsByForum", time.Now(), &err)
+	return m.storage.GetThreadsByForum(ctx, input)
+}
+
+func (m *measured) CheckThreadIfExists(ctx context.Context, input models.ThreadInput) (thread models.ThreadInput, err error) {
+	defer m.queries.Observe("thread", "CheckThreadIfExists", time.Now(), &err)
+	return m.storage.CheckThreadIfExists(ctx, input)
+}
+
+func (m *measured) GetThreadForPost(ctx context.Context, input models.ThreadInput, post *models.Thread) (err error) {
+	defer m.queries.Observe("thread", "GetThreadForPost", time.Now(), &err)
+	return m.storage.GetThreadForPost(ctx, input, post)
+}
+
+func (m *measured) GetForumByThread(ctx context.Context, input *models.ThreadInput) (forum string, err error) {
+	defer m.queries.Observe("thread", "GetForumByThread", time.Now(), &err)
+	return m.storage.GetForumByThread(ctx, input)
+}
+
+func (m *measured) DeleteThread(ctx context.Context, input models.ThreadInput) (thread models.Thread, err error) {
+	defer m.queries.Observe("thread", "DeleteThread", time.Now(), &err)
+	return m.storage.DeleteThread(ctx, input)
+}
+
+func (m *measured) ArchiveThread(ctx context.Context, input models.ThreadInput) (thread models.Thread, err error) {
+	defer m.queries.Observe("thread", "ArchiveThread", time.Now(), &err)
+	return m.storage.ArchiveThread(ctx, input)
+}
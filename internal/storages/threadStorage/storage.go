@@ -1,21 +1,24 @@
 package threadStorage
 
 import (
+	"context"
 	"database/sql"
-	"fmt"
 	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx"
+	"github.com/pringleskate/tp_db_forum/internal/logger"
 	"github.com/pringleskate/tp_db_forum/internal/models"
 )
 
 type Storage interface {
-	CreateThread(input models.Thread) (thread models.Thread, err error)
-	GetDetails(input models.ThreadInput) (thread models.Thread, err error)
-	UpdateThread(input models.ThreadUpdate) (thread models.Thread, err error)
-	GetThreadsByForum(input models.ForumGetThreads) (threads []models.Thread, err error)
-	CheckThreadIfExists(input models.ThreadInput) (thread models.ThreadInput, err error)
-	GetThreadForPost(input models.ThreadInput, post *models.Thread) (err error)
-	GetForumByThread(input *models.ThreadInput) (forum string, err error)
+	CreateThread(ctx context.Context, input models.Thread) (thread models.Thread, err error)
+	GetDetails(ctx context.Context, input models.ThreadInput) (thread models.Thread, err error)
+	UpdateThread(ctx context.Context, input models.ThreadUpdate) (thread models.Thread, err error)
+	GetThreadsByForum(ctx context.Context, input models.ForumGetThreads) (threads []models.Thread, err error)
+	CheckThreadIfExists(ctx context.Context, input models.ThreadInput) (thread models.ThreadInput, err error)
+	GetThreadForPost(ctx context.Context, input models.ThreadInput, post *models.Thread) (err error)
+	GetForumByThread(ctx context.Context, input *models.ThreadInput) (forum string, err error)
+	DeleteThread(ctx context.Context, input models.ThreadInput) (thread models.Thread, err error)
+	ArchiveThread(ctx context.Context, input models.ThreadInput) (thread models.Thread, err error)
 }
 
 type storage struct {
@@ -33,54 +36,91 @@ var (
 	insertWithSlug = "INSERT INTO threads (author, created, forum, message, slug, title, votes) VALUES ((SELECT u.nickname FROM users u WHERE u.nickname = $1), $2, (SELECT f.slug FROM forums f WHERE f.slug = $3), $4, $5, $6, $7) RETURNING ID, author, created, forum, message, slug, title, votes"
 	insertWithoutSlug = "INSERT INTO threads (author, created, forum, message, title, votes) VALUES ((SELECT u.nickname FROM users u WHERE u.nickname = $1), $2, (SELECT f.slug FROM forums f WHERE f.slug = $3), $4, $5, $6) RETURNING ID, author, created, forum, message, title, votes"
 
-	selectBySlug = "SELECT author, created, forum, ID, message, slug, title, votes FROM threads WHERE slug = $1"
-	selectByID = "SELECT author, created, forum, ID, message, slug, title, votes FROM threads WHERE ID = $1"
+	selectBySlug = "SELECT author, created, forum, ID, message, slug, title, votes, archived FROM threads WHERE slug = $1"
+	selectByID = "SELECT author, created, forum, ID, message, slug, title, votes, archived FROM threads WHERE ID = $1"
 
-	selectThreads = "SELECT id, slug, author, created, forum, title, message, votes FROM threads WHERE forum = $1 ORDER BY created LIMIT $2"
-	selectThreadsSince = "SELECT id, slug, author, created, forum, title, message, votes FROM threads WHERE forum = $1 AND created >= $2 ORDER BY created LIMIT $3"
-	selectThreadsDesc = "SELECT id, slug, author, created, forum, title, message, votes FROM threads WHERE forum = $1 ORDER BY created DESC LIMIT $2"
-	selectThreadsSinceDesc =  "SELECT id, slug, author, created, forum, title, message, votes FROM threads WHERE forum = $1 AND created <= $2 ORDER BY created DESC LIMIT $3"
+	selectThreads = "SELECT id, slug, author, created, forum, title, message, votes, archived FROM threads WHERE forum = $1 AND (NOT archived OR $3) ORDER BY created LIMIT $2"
+	selectThreadsSince = "SELECT id, slug, author, created, forum, title, message, votes, archived FROM threads WHERE forum = $1 AND created >= $2 AND (NOT archived OR $4) ORDER BY created LIMIT $3"
+	selectThreadsDesc = "SELECT id, slug, author, created, forum, title, message, votes, archived FROM threads WHERE forum = $1 AND (NOT archived OR $3) ORDER BY created DESC LIMIT $2"
+	selectThreadsSinceDesc =  "SELECT id, slug, author, created, forum, title, message, votes, archived FROM threads WHERE forum = $1 AND created <= $2 AND (NOT archived OR $4) ORDER BY created DESC LIMIT $3"
+
+	updateForumThreads = "UPDATE forums SET threads = threads + 1, activity = greatest(activity, $2) WHERE slug = $1"
+	insertForumUser = "INSERT INTO forum_users (forumID, userID) SELECT f.ID, u.ID FROM forums f, users u WHERE f.slug = $1 AND u.nickname = $2 ON CONFLICT DO NOTHING"
+
+	lockBySlug = "SELECT ID FROM threads WHERE slug = $1 FOR UPDATE"
+	lockByID = "SELECT ID FROM threads WHERE ID = $1 FOR UPDATE"
+	deleteThreadVotes = "DELETE FROM votes WHERE thread = $1"
+	deleteThreadPosts = "WITH deleted AS (DELETE FROM posts WHERE thread = $1 RETURNING deleted) SELECT count(*) FILTER (WHERE NOT deleted) FROM deleted"
+	deleteThread = "DELETE FROM threads WHERE ID = $1 RETURNING author, created, forum, ID, message, slug, title, votes, archived"
+	updateForumCounters = "UPDATE forums SET threads = threads - 1, posts = posts - $2 WHERE slug = $1"
+	archiveThread = "UPDATE threads SET archived = true WHERE ID = $1 RETURNING author, created, forum, ID, message, slug, title, votes, archived"
 )
 
-func (s *storage) CreateThread(input models.Thread) (thread models.Thread, err error) {
+func (s *storage) CreateThread(ctx context.Context, input models.Thread) (thread models.Thread, err error) {
+	tx, err := s.db.BeginEx(ctx, nil)
+	if err != nil {
+		return thread, models.ErrInternal.Wrap(err)
+	}
+	defer tx.Rollback()
+
 	if input.Slug == "" {
-		err = s.db.QueryRow(insertWithoutSlug, input.Author, input.Created, input.Forum, input.Message, input.Title, input.Votes).
+		err = tx.QueryRowEx(ctx, insertWithoutSlug, nil, input.Author, input.Created, input.Forum, input.Message, input.Title, input.Votes).
 					Scan(&thread.ID, &thread.Author, &thread.Created, &thread.Forum, &thread.Message, &thread.Title, &thread.Votes)
 	} else {
-		err = s.db.QueryRow(insertWithSlug, input.Author, input.Created, input.Forum, input.Message, input.Slug, input.Title, input.Votes).
+		err = tx.QueryRowEx(ctx, insertWithSlug, nil, input.Author, input.Created, input.Forum, input.Message, input.Slug, input.Title, input.Votes).
 					Scan(&thread.ID, &thread.Author, &thread.Created, &thread.Forum, &thread.Message, &thread.Slug, &thread.Title, &thread.Votes)
 	}
 
 	if pqErr, ok := err.(pgx.PgError); ok {
 		switch pqErr.Code {
 		case pgerrcode.UniqueViolation:
-			return thread, models.Error{Code: "409"}
+			return thread, models.ErrConflict
 		case pgerrcode.NotNullViolation, pgerrcode.ForeignKeyViolation:
-			return thread, models.Error{Code: "404"}
+			return thread, models.ErrNotFound
 		default:
-			return thread, models.Error{Code: "500"}
+			return thread, models.ErrInternal.Wrap(err)
 		}
 	}
+	if err != nil {
+		return thread, models.ErrInternal.Wrap(err)
+	}
+
+	_, err = tx.ExecEx(ctx, updateForumThreads, nil, thread.Forum, thread.Created)
+	if err != nil {
+		logger.FromContext(ctx).Error("query failed", "op", "threadStorage.CreateThread", "err", err)
+		return thread, models.ErrInternal.Wrap(err)
+	}
+
+	_, err = tx.ExecEx(ctx, insertForumUser, nil, thread.Forum, thread.Author)
+	if err != nil {
+		logger.FromContext(ctx).Error("query failed", "op", "threadStorage.CreateThread", "err", err)
+		return thread, models.ErrInternal.Wrap(err)
+	}
+
+	if err = tx.CommitEx(ctx); err != nil {
+		logger.FromContext(ctx).Error("query failed", "op", "threadStorage.CreateThread", "err", err)
+		return thread, models.ErrInternal.Wrap(err)
+	}
 
 	return
 }
 
-func (s *storage) GetDetails(input models.ThreadInput) (thread models.Thread, err error) {
+func (s *storage) GetDetails(ctx context.Context, input models.ThreadInput) (thread models.Thread, err error) {
 	slug := sql.NullString{}
 	if input.Slug == "" {
-		err = s.db.QueryRow(selectByID, input.ThreadID).
-					Scan(&thread.Author, &thread.Created, &thread.Forum, &thread.ID, &thread.Message, &slug, &thread.Title, &thread.Votes)
+		err = s.db.QueryRowEx(ctx, selectByID, nil, input.ThreadID).
+					Scan(&thread.Author, &thread.Created, &thread.Forum, &thread.ID, &thread.Message, &slug, &thread.Title, &thread.Votes, &thread.IsArchived)
 	} else {
-		err = s.db.QueryRow(selectBySlug, input.Slug).
-			Scan(&thread.Author, &thread.Created, &thread.Forum, &thread.ID, &thread.Message, &slug, &thread.Title, &thread.Votes)
+		err = s.db.QueryRowEx(ctx, selectBySlug, nil, input.Slug).
+			Scan(&thread.Author, &thread.Created, &thread.Forum, &thread.ID, &thread.Message, &slug, &thread.Title, &thread.Votes, &thread.IsArchived)
 	}
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return thread, models.Error{Code: "404"}
+			return thread, models.ErrNotFound
 
 		}
-		return thread, models.Error{Code: "500"}
+		return thread, models.ErrInternal.Wrap(err)
 	}
 
 	if slug.Valid {
@@ -90,57 +130,61 @@ func (s *storage) GetDetails(input models.ThreadInput) (thread models.Thread, er
 	return
 }
 
-func (s *storage) UpdateThread(input models.ThreadUpdate) (thread models.Thread, err error) {
+func (s *storage) UpdateThread(ctx context.Context, input models.ThreadUpdate) (thread models.Thread, err error) {
 	if input.Title != "" && input.Message != "" {
-		err = s.db.QueryRow("UPDATE threads SET message = $1, title = $2 WHERE ID = $3 OR slug = $4 " +
-								"RETURNING author, created, forum, ID, message, slug, title, votes",
+		err = s.db.QueryRowEx(ctx, "UPDATE threads SET message = $1, title = $2 WHERE (ID = $3 OR slug = $4) AND NOT archived " +
+								"RETURNING author, created, forum, ID, message, slug, title, votes", nil,
 							input.Message, input.Title, input.ThreadID, input.Slug).
 					Scan(&thread.Author, &thread.Created, &thread.Forum, &thread.ID, &thread.Message, &thread.Slug, &thread.Title, &thread.Votes)
 
 	} else if input.Title != "" && input.Message == "" {
-		err = s.db.QueryRow("UPDATE threads SET title = $1 WHERE ID = $2 OR slug = $3 " +
-								"RETURNING author, created, forum, ID, message, slug, title, votes",
+		err = s.db.QueryRowEx(ctx, "UPDATE threads SET title = $1 WHERE (ID = $2 OR slug = $3) AND NOT archived " +
+								"RETURNING author, created, forum, ID, message, slug, title, votes", nil,
 								input.Title, input.ThreadID, input.Slug).
 					Scan(&thread.Author, &thread.Created, &thread.Forum, &thread.ID, &thread.Message, &thread.Slug, &thread.Title, &thread.Votes)
 
 	} else if input.Title == "" && input.Message != "" {
-		err = s.db.QueryRow("UPDATE threads SET message = $1 WHERE ID = $2 OR slug = $3 " +
-			"RETURNING author, created, forum, ID, message, slug, title, votes",
+		err = s.db.QueryRowEx(ctx, "UPDATE threads SET message = $1 WHERE (ID = $2 OR slug = $3) AND NOT archived " +
+			"RETURNING author, created, forum, ID, message, slug, title, votes", nil,
 			input.Message, input.ThreadID, input.Slug).
 			Scan(&thread.Author, &thread.Created, &thread.Forum, &thread.ID, &thread.Message, &thread.Slug, &thread.Title, &thread.Votes)
 
 
 	} else if input.Title == "" && input.Message == "" {
-		err = s.db.QueryRow("SELECT author, created, forum, ID, message, slug, title, votes FROM threads WHERE ID = $1 OR slug = $2", input.ThreadID, input.Slug).
-					Scan(&thread.Author, &thread.Created, &thread.Forum, &thread.ID, &thread.Message, &thread.Slug, &thread.Title, &thread.Votes)
+		err = s.db.QueryRowEx(ctx, "SELECT author, created, forum, ID, message, slug, title, votes, archived FROM threads WHERE ID = $1 OR slug = $2", nil, input.ThreadID, input.Slug).
+					Scan(&thread.Author, &thread.Created, &thread.Forum, &thread.ID, &thread.Message, &thread.Slug, &thread.Title, &thread.Votes, &thread.IsArchived)
 	}
 
 	if err != nil {
-		fmt.Println(err)
 		if err == pgx.ErrNoRows {
-			return thread, models.Error{Code: "404"}
+			/* the update skips archived rows, tell them apart from missing ones */
+			if _, checkErr := s.CheckThreadIfExists(ctx, input.ThreadInput); checkErr == nil {
+				return thread, models.ErrConflict.WithCode("thread_archived").WithMessage("thread is archived")
+			}
+			return thread, models.ErrNotFound
 
 		}
-		return thread, models.Error{Code: "500"}
+		logger.FromContext(ctx).Error("query failed", "op", "threadStorage.UpdateThread", "err", err)
+		return thread, models.ErrInternal.Wrap(err)
 	}
 
 	return
 }
 
-func (s *storage) GetThreadsByForum(input models.ForumGetThreads) (threads []models.Thread, err error) {
+func (s *storage) GetThreadsByForum(ctx context.Context, input models.ForumGetThreads) (threads []models.Thread, err error) {
 	var rows *pgx.Rows
 	if input.Since == "" && !input.Desc {
-		rows, err = s.db.Query(selectThreads, input.Slug, input.Limit)
+		rows, err = s.db.QueryEx(ctx, selectThreads, nil, input.Slug, input.Limit, input.Archived)
 	} else if input.Since == "" && input.Desc {
-		rows, err = s.db.Query(selectThreadsDesc,  input.Slug, input.Limit)
+		rows, err = s.db.QueryEx(ctx, selectThreadsDesc, nil,  input.Slug, input.Limit, input.Archived)
 	}  else if input.Since != "" && !input.Desc {
-		rows, err = s.db.Query(selectThreadsSince,  input.Slug, input.Since, input.Limit)
+		rows, err = s.db.QueryEx(ctx, selectThreadsSince, nil,  input.Slug, input.Since, input.Limit, input.Archived)
 	} else if input.Since != "" && input.Desc {
-		rows, err = s.db.Query(selectThreadsSinceDesc, input.Slug, input.Since, input.Limit)
+		rows, err = s.db.QueryEx(ctx, selectThreadsSinceDesc, nil, input.Slug, input.Since, input.Limit, input.Archived)
 	}
 
 	if err != nil {
-		return threads, models.Error{Code: "500"}
+		return threads, models.ErrInternal.Wrap(err)
 	}
 	defer rows.Close()
 
@@ -149,9 +193,9 @@ func (s *storage) GetThreadsByForum(input models.ForumGetThreads) (threads []mod
 		thread := models.Thread{}
 		slug := sql.NullString{}
 
-		err = rows.Scan(&thread.ID, &slug, &thread.Author, &thread.Created, &thread.Forum, &thread.Title, &thread.Message, &thread.Votes)
+		err = rows.Scan(&thread.ID, &slug, &thread.Author, &thread.Created, &thread.Forum, &thread.Title, &thread.Message, &thread.Votes, &thread.IsArchived)
 		if err != nil {
-			return threads, models.Error{Code: "500"}
+			return threads, models.ErrInternal.Wrap(err)
 		}
 
 		if slug.Valid {
@@ -161,33 +205,40 @@ func (s *storage) GetThreadsByForum(input models.ForumGetThreads) (threads []mod
 		threads = append(threads, thread)
 	}
 
+	if err = rows.Err(); err != nil {
+		return threads, models.ErrInternal.Wrap(err)
+	}
+
 	return
 }
 
-func (s storage) CheckThreadIfExists(input models.ThreadInput) (thread models.ThreadInput, err error) {
+func (s storage) CheckThreadIfExists(ctx context.Context, input models.ThreadInput) (thread models.ThreadInput, err error) {
 	if input.Slug == "" {
-		err = s.db.QueryRow("SELECT ID from threads WHERE ID = $1", input.ThreadID).Scan(&thread.ThreadID)
+		err = s.db.QueryRowEx(ctx, "SELECT ID from threads WHERE ID = $1", nil, input.ThreadID).Scan(&thread.ThreadID)
 	} else {
-		err = s.db.QueryRow("SELECT ID from threads WHERE slug = $1", input.Slug).Scan(&thread.ThreadID)
+		err = s.db.QueryRowEx(ctx, "SELECT ID from threads WHERE slug = $1", nil, input.Slug).Scan(&thread.ThreadID)
 	}
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return thread, models.Error{Code: "404"}
+			return thread, models.ErrNotFound
 		}
-		return thread, models.Error{Code: "500"}
+		return thread, models.ErrInternal.Wrap(err)
 	}
 
 	return
 }
 
-func (s *storage) GetThreadForPost(input models.ThreadInput, thread *models.Thread) (err error) {
+func (s *storage) GetThreadForPost(ctx context.Context, input models.ThreadInput, thread *models.Thread) (err error) {
 	slug := sql.NullString{}
-	err = s.db.QueryRow(selectByID, input.ThreadID).
-				Scan(&thread.Author, &thread.Created, &thread.Forum, &thread.ID, &thread.Message, &slug, &thread.Title, &thread.Votes)
+	err = s.db.QueryRowEx(ctx, selectByID, nil, input.ThreadID).
+				Scan(&thread.Author, &thread.Created, &thread.Forum, &thread.ID, &thread.Message, &slug, &thread.Title, &thread.Votes, &thread.IsArchived)
 
 	if err != nil {
-		return models.Error{Code: "500"}
+		if err == pgx.ErrNoRows {
+			return models.ErrNotFound
+		}
+		return models.ErrInternal.Wrap(err)
 	}
 
 	if slug.Valid {
@@ -197,18 +248,102 @@ func (s *storage) GetThreadForPost(input models.ThreadInput, thread *models.Thre
 	return
 }
 
-func (s *storage) GetForumByThread(input *models.ThreadInput) (forum string, err error) {
+func (s *storage) GetForumByThread(ctx context.Context, input *models.ThreadInput) (forum string, err error) {
+	if input.Slug == "" {
+		err = s.db.QueryRowEx(ctx, "SELECT forum FROM threads WHERE ID = $1", nil, input.ThreadID).Scan(&forum)
+	} else {
+		err = s.db.QueryRowEx(ctx, "SELECT forum, ID FROM threads WHERE slug = $1", nil, input.Slug).Scan(&forum, &input.ThreadID)
+	}
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return forum, models.ErrNotFound
+		}
+
+		return forum, models.ErrInternal.Wrap(err)
+	}
+
+	return
+}
+
+/*
+	Hard delete: votes and posts go with the thread and the forum counters are lowered.
+	Posts that were already soft deleted have been subtracted from forums.posts before.
+*/
+func (s *storage) DeleteThread(ctx context.Context, input models.ThreadInput) (thread models.Thread, err error) {
+	tx, err := s.db.BeginEx(ctx, nil)
+	if err != nil {
+		return thread, models.ErrInternal.Wrap(err)
+	}
+	defer tx.Rollback()
+
 	if input.Slug == "" {
-		err = s.db.QueryRow("SELECT forum FROM threads WHERE ID = $1", input.ThreadID).Scan(&forum)
+		err = tx.QueryRowEx(ctx, lockByID, nil, input.ThreadID).Scan(&input.ThreadID)
 	} else {
-		err = s.db.QueryRow("SELECT forum, ID FROM threads WHERE slug = $1", input.Slug).Scan(&forum, &input.ThreadID)
+		err = tx.QueryRowEx(ctx, lockBySlug, nil, input.Slug).Scan(&input.ThreadID)
 	}
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return forum, models.Error{Code: "404"}
+			return thread, models.ErrNotFound
 		}
+		return thread, models.ErrInternal.Wrap(err)
+	}
+
+	if _, err = tx.ExecEx(ctx, deleteThreadVotes, nil, input.ThreadID); err != nil {
+		logger.FromContext(ctx).Error("query failed", "op", "threadStorage.DeleteThread", "err", err)
+		return thread, models.ErrInternal.Wrap(err)
+	}
 
-		return forum, models.Error{Code: "500"}
+	var posts int
+	if err = tx.QueryRowEx(ctx, deleteThreadPosts, nil, input.ThreadID).Scan(&posts); err != nil {
+		logger.FromContext(ctx).Error("query failed", "op", "threadStorage.DeleteThread", "err", err)
+		return thread, models.ErrInternal.Wrap(err)
+	}
+
+	slug := sql.NullString{}
+	err = tx.QueryRowEx(ctx, deleteThread, nil, input.ThreadID).
+		Scan(&thread.Author, &thread.Created, &thread.Forum, &thread.ID, &thread.Message, &slug, &thread.Title, &thread.Votes, &thread.IsArchived)
+	if err != nil {
+		logger.FromContext(ctx).Error("query failed", "op", "threadStorage.DeleteThread", "err", err)
+		return thread, models.ErrInternal.Wrap(err)
+	}
+
+	if _, err = tx.ExecEx(ctx, updateForumCounters, nil, thread.Forum, posts); err != nil {
+		logger.FromContext(ctx).Error("query failed", "op", "threadStorage.DeleteThread", "err", err)
+		return thread, models.ErrInternal.Wrap(err)
+	}
+
+	if err = tx.CommitEx(ctx); err != nil {
+		logger.FromContext(ctx).Error("query failed", "op", "threadStorage.DeleteThread", "err", err)
+		return thread, models.ErrInternal.Wrap(err)
+	}
+
+	if slug.Valid {
+		thread.Slug = slug.String
+	}
+
+	return
+}
+
+/* archived threads stay readable and counted but refuse edits, votes and new posts */
+func (s *storage) ArchiveThread(ctx context.Context, input models.ThreadInput) (thread models.Thread, err error) {
+	if input.Slug != "" {
+		if input, err = s.CheckThreadIfExists(ctx, input); err != nil {
+			return thread, err
+		}
+	}
+
+	slug := sql.NullString{}
+	err = s.db.QueryRowEx(ctx, archiveThread, nil, input.ThreadID).
+		Scan(&thread.Author, &thread.Created, &thread.Forum, &thread.ID, &thread.Message, &slug, &thread.Title, &thread.Votes, &thread.IsArchived)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return thread, models.ErrNotFound
+		}
+		return thread, models.ErrInternal.Wrap(err)
+	}
+
+	if slug.Valid {
+		thread.Slug = slug.String
 	}
 
 	return
@@ -0,0 +1,51 @@
+package userStorage
+
+import (
+	"context"
+	"github.com/pringleskate/tp_db_forum/internal/metrics"
+	"github.com/pringleskate/tp_db_forum/internal/models"
+	"time"
+)
+
+/* times every call of the wrapped user storage, postgres or memory alike */
+type measured struct {
+	storage Storage
+	queries *metrics.Queries
+}
+
+func WithMetrics(storage Storage, queries *metrics.Queries) Storage {
+	return &measured{
+		storage: storage,
+		queries: queries,
+	}
+}
+
+func (m *measured) CreateUser(ctx context.Context, input models.User, passwordHash []byte) (user models.User, err error) {
+	defer m.queries.Observe("user", "CreateUser", time.Now(), &err)
+	return m.storage.CreateUser(ctx, input, passwordHash)
+}
+
+func (m *measured) GetProfile(ctx context.Context, input string) (user models.User, err error) {
+	defer m.queries.Observe("user", "GetProfile", time.Now(), &err)
+	return m.storage.GetProfile(ctx, input)
+}
+
+func (m *measured) UpdateProfile(ctx context.Context, input models.User) (user models.User, err error) {
+	defer m.queries.Observe("user", "UpdateProfile", time.Now(), &err)
+	return m.storage.UpdateProfile(ctx, input)
+}
+
+func (m *measured) GetUsers(ctx context.Context, input models.ForumGetUsers, forumID int) (users []models.User, err error) {
+	defer m.queries.Observe("user", "GetUsers", time.Now(), &err)
+	return m.storage.GetUsers(ctx, input, forumID)
+}
+
+func (m *measured) GetUserForPost(ctx context.Context, input string, user *models.User) (err error) {
+	defer m.queries.Observe("user", "GetUserForPost", time.Now(), &err)
+	return m.storage.GetUserForPost(ctx, input, user)
+}
+
+func (m *measured) GetEmailConflictUser(ctx context.Context, email string) (user models.User, err error) {
+	defer m.queries.Observe("user", "GetEmailConflictUser", time.Now(), &err)
+	return m.storage.GetEmailConflictUser(ctx, email)
+}
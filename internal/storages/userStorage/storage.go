@@ -1,21 +1,21 @@
 package userStorage
 
 import (
-	"fmt"
+	"context"
 	//"fmt"
 	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx"
+	"github.com/pringleskate/tp_db_forum/internal/logger"
 	"github.com/pringleskate/tp_db_forum/internal/models"
 )
 
 type Storage interface {
-	CreateUser(input models.User) (user models.User, err error)
-	GetProfile(input string) (user models.User, err error)
-	UpdateProfile(input models.User) (user models.User, err error)
-	GetUsers(input models.ForumGetUsers, forumID int) (users []models.User, err error)
-	GetUserForPost(input string,  user *models.User) (err error)
-	GetUserIDByNickname(input string) (userID int, err error)
-	GetEmailConflictUser(email string) (user models.User, err error)
+	CreateUser(ctx context.Context, input models.User, passwordHash []byte) (user models.User, err error)
+	GetProfile(ctx context.Context, input string) (user models.User, err error)
+	UpdateProfile(ctx context.Context, input models.User) (user models.User, err error)
+	GetUsers(ctx context.Context, input models.ForumGetUsers, forumID int) (users []models.User, err error)
+	GetUserForPost(ctx context.Context, input string,  user *models.User) (err error)
+	GetEmailConflictUser(ctx context.Context, email string) (user models.User, err error)
 }
 
 type storage struct {
@@ -44,16 +44,17 @@ var (
 	updateFullnameAbout = "UPDATE users SET nickname = $1, fullname = $2, about = $3 WHERE nickname = $4 RETURNING fullname, email, about, nickname"
 )
 
-func (s *storage) CreateUser(input models.User) (user models.User, err error) {
-	_, err = s.db.Exec("INSERT INTO users (nickname, email, fullname, about) VALUES ($1, $2, $3, $4)",
-						input.Nickname, input.Email, input.Fullname, input.About)
+/* passwordHash may be nil, such a user cannot log in until a password is set */
+func (s *storage) CreateUser(ctx context.Context, input models.User, passwordHash []byte) (user models.User, err error) {
+	_, err = s.db.ExecEx(ctx, "INSERT INTO users (nickname, email, fullname, about, password_hash) VALUES ($1, $2, $3, $4, $5)", nil,
+						input.Nickname, input.Email, input.Fullname, input.About, passwordHash)
 
 	if pqErr, ok := err.(pgx.PgError); ok {
 		switch pqErr.Code {
 		case pgerrcode.UniqueViolation:
-			return user, models.Error{Code: "409", Message: "conflict user"}
+			return user, models.ErrConflict.WithMessage("conflict user")
 		default:
-			return user, models.Error{Code: "500"}
+			return user, models.ErrInternal.Wrap(err)
 		}
 	}
 
@@ -65,78 +66,78 @@ func (s *storage) CreateUser(input models.User) (user models.User, err error) {
 	return
 }
 
-func (s *storage) GetProfile(input string) (user models.User, err error) {
-	err = s.db.QueryRow("SELECT fullname, email, about, nickname FROM users WHERE nickname = $1", input).
+func (s *storage) GetProfile(ctx context.Context, input string) (user models.User, err error) {
+	err = s.db.QueryRowEx(ctx, "SELECT fullname, email, about, nickname FROM users WHERE nickname = $1", nil, input).
 				Scan(&user.Fullname, &user.Email, &user.About, &user.Nickname)
 
 	if err != nil {
-		fmt.Println(err)
 		if err == pgx.ErrNoRows {
-			return user, models.Error{Code: "404"}
+			return user, models.ErrNotFound
 
 		}
-		return user, models.Error{Code: "500"}
+		logger.FromContext(ctx).Error("query failed", "op", "userStorage.GetProfile", "err", err)
+		return user, models.ErrInternal.Wrap(err)
 	}
 
 	return
 }
 
-func (s *storage) UpdateProfile(input models.User) (user models.User, err error) {
+func (s *storage) UpdateProfile(ctx context.Context, input models.User) (user models.User, err error) {
 	if input.About != "" && input.Email != "" && input.Fullname != "" {
-		err = s.db.QueryRow(updateFull, input.Nickname, input.Fullname, input.Email, input.About, input.Nickname).
+		err = s.db.QueryRowEx(ctx, updateFull, nil, input.Nickname, input.Fullname, input.Email, input.About, input.Nickname).
 					Scan(&user.Fullname, &user.Email, &user.About, &user.Nickname)
 	} else if input.About != "" && input.Email != "" {
-		err = s.db.QueryRow(updateEmailAbout, input.Nickname, input.Email, input.About, input.Nickname).
+		err = s.db.QueryRowEx(ctx, updateEmailAbout, nil, input.Nickname, input.Email, input.About, input.Nickname).
 					Scan(&user.Fullname, &user.Email, &user.About, &user.Nickname)
 	} else if input.Email != "" && input.Fullname != "" {
-		err = s.db.QueryRow(updateEmailFullname, input.Nickname, input.Fullname, input.Email, input.Nickname).
+		err = s.db.QueryRowEx(ctx, updateEmailFullname, nil, input.Nickname, input.Fullname, input.Email, input.Nickname).
 					Scan(&user.Fullname, &user.Email, &user.About, &user.Nickname)
 	} else if input.About != "" && input.Fullname != "" {
-		err = s.db.QueryRow(updateFullnameAbout, input.Nickname, input.Fullname, input.About, input.Nickname).
+		err = s.db.QueryRowEx(ctx, updateFullnameAbout, nil, input.Nickname, input.Fullname, input.About, input.Nickname).
 					Scan(&user.Fullname, &user.Email, &user.About, &user.Nickname)
 	} else if input.About != "" {
-		err = s.db.QueryRow(updateAbout, input.Nickname, input.About, input.Nickname).
+		err = s.db.QueryRowEx(ctx, updateAbout, nil, input.Nickname, input.About, input.Nickname).
 					Scan(&user.Fullname, &user.Email, &user.About, &user.Nickname)
 	} else if input.Fullname != "" {
-		err = s.db.QueryRow(updateFullname, input.Nickname, input.Fullname, input.Nickname).
+		err = s.db.QueryRowEx(ctx, updateFullname, nil, input.Nickname, input.Fullname, input.Nickname).
 					Scan(&user.Fullname, &user.Email, &user.About, &user.Nickname)
 	} else if input.Email != "" {
-		err = s.db.QueryRow(updateEmail, input.Nickname, input.Email, input.Nickname).
+		err = s.db.QueryRowEx(ctx, updateEmail, nil, input.Nickname, input.Email, input.Nickname).
 					Scan(&user.Fullname, &user.Email, &user.About, &user.Nickname)
 	}
 
 	if err == pgx.ErrNoRows {
-		return user, models.Error{Code: "404"}
+		return user, models.ErrNotFound
 	}
 
 	if pqErr, ok := err.(pgx.PgError); ok {
 		switch pqErr.Code {
 		case pgerrcode.UniqueViolation:
-			return user, models.Error{Code: "409"}
+			return user, models.ErrConflict
 		default:
-			return user, models.Error{Code: "500"}
+			return user, models.ErrInternal.Wrap(err)
 		}
 	}
 
 	return
 }
 
-func (s *storage) GetUsers(input models.ForumGetUsers, forumID int) (users []models.User, err error) {
+func (s *storage) GetUsers(ctx context.Context, input models.ForumGetUsers, forumID int) (users []models.User, err error) {
 	var rows *pgx.Rows
 	users = make([]models.User, 0)
 	if input.Since == "" && !input.Desc {
-		rows, err = s.db.Query(selectEmpty, forumID, input.Limit)
+		rows, err = s.db.QueryEx(ctx, selectEmpty, nil, forumID, input.Limit)
 	} else if input.Since == "" && input.Desc {
-		rows, err = s.db.Query(selectWithDesc, forumID, input.Limit)
+		rows, err = s.db.QueryEx(ctx, selectWithDesc, nil, forumID, input.Limit)
 	}  else if input.Since != "" && !input.Desc {
-		rows, err = s.db.Query(selectWithSince, forumID, input.Since, input.Limit)
+		rows, err = s.db.QueryEx(ctx, selectWithSince, nil, forumID, input.Since, input.Limit)
 	} else if input.Since != "" && input.Desc {
-		rows, err = s.db.Query(selectWithSinceDesc, forumID, input.Since, input.Limit)
+		rows, err = s.db.QueryEx(ctx, selectWithSinceDesc, nil, forumID, input.Since, input.Limit)
 	}
 
 	if err != nil {
-		fmt.Println(err)
-		return users, models.Error{Code: "500"}
+		logger.FromContext(ctx).Error("query failed", "op", "userStorage.GetUsers", "err", err)
+		return users, models.ErrInternal.Wrap(err)
 	}
 
 	defer rows.Close()
@@ -146,47 +147,45 @@ func (s *storage) GetUsers(input models.ForumGetUsers, forumID int) (users []mod
 
 		err = rows.Scan(&user.Nickname, &user.Fullname, &user.About, &user.Email)
 		if err != nil {
-			return users, models.Error{Code: "500"}
+			return users, models.ErrInternal.Wrap(err)
 		}
 
 		users = append(users, user)
 	}
 
+	if err = rows.Err(); err != nil {
+		return users, models.ErrInternal.Wrap(err)
+	}
+
 	return
 }
 
-func (s *storage) GetUserForPost(input string, user *models.User) (err error) {
+func (s *storage) GetUserForPost(ctx context.Context, input string, user *models.User) (err error) {
 	user.Nickname = input
-	err = s.db.QueryRow("SELECT fullname, email, about FROM users WHERE nickname = $1", input).
+	err = s.db.QueryRowEx(ctx, "SELECT fullname, email, about FROM users WHERE nickname = $1", nil, input).
 		Scan(&user.Fullname, &user.Email, &user.About)
 
 	if err != nil {
-		return models.Error{Code: "500"}
-	}
-
-	return
-}
-
-func (s *storage) GetUserIDByNickname(input string) (userID int, err error) {
-	err = s.db.QueryRow("SELECT ID FROM users WHERE nickname = $1", input).Scan(&userID)
-	if err != nil {
-		return userID, models.Error{Code: "500"}
+		if err == pgx.ErrNoRows {
+			return models.ErrNotFound
+		}
+		return models.ErrInternal.Wrap(err)
 	}
 
 	return
 }
 
-func (s *storage) GetEmailConflictUser(email string) (user models.User, err error) {
-	err = s.db.QueryRow("SELECT fullname, nickname, about, email FROM users WHERE email = $1", email).
+func (s *storage) GetEmailConflictUser(ctx context.Context, email string) (user models.User, err error) {
+	err = s.db.QueryRowEx(ctx, "SELECT fullname, nickname, about, email FROM users WHERE email = $1", nil, email).
 		Scan(&user.Fullname, &user.Nickname, &user.About, &user.Email)
 
 	if err != nil {
-		fmt.Println(err)
 		if err == pgx.ErrNoRows {
-			return user, models.Error{Code: "404"}
+			return user, models.ErrNotFound
 
 		}
-		return user, models.Error{Code: "500"}
+		logger.FromContext(ctx).Error("query failed", "op", "userStorage.GetEmailConflictUser", "err", err)
+		return user, models.ErrInternal.Wrap(err)
 	}
 
 	return
@@ -0,0 +1,51 @@
+package voteStorage
+
+import (
+	"context"
+	"github.com/pringleskate/tp_db_forum/internal/metrics"
+	"github.com/pringleskate/tp_db_forum/internal/models"
+	"time"
+)
+
+/* times every call of the wrapped vote storage, postgres or memory alike */
+type measured struct {
+	storage Storage
+	queries *metrics.Queries
+}
+
+func WithMetrics(storage Storage, queries *metrics.Queries) Storage {
+	return &measured{
+		storage: storage,
+		queries: queries,
+	}
+}
+
+func (m *measured) CreateVote(ctx context.Context, vote models.Vote) (thread models.Thread, err error) {
+	defer m.queries.Observe("vote", "CreateVote", time.Now(), &err)
+	return m.storage.CreateVote(ctx, vote)
+}
+
+func (m *measured) CreatePostVote(ctx context.Context, vote models.Vote) (post models.Post, err error) {
+	defer m.queries.Observe("vote", "CreatePostVote", time.Now(), &err)
+	return m.storage.CreatePostVote(ctx, vote)
+}
+
+func (m *measured) DeleteVote(ctx context.Context, vote models.Vote) (thread models.Thread, err error) {
+	defer m.queries.Observe("vote", "DeleteVote", time.Now(), &err)
+	return m.storage.DeleteVote(ctx, vote)
+}
+
+func (m *measured) DeletePostVote(ctx context.Context, vote models.Vote) (post models.Post, err error) {
+	defer m.queries.Observe("vote", "DeletePostVote", time.Now(), &err)
+	return m.storage.DeletePostVote(ctx, vote)
+}
+
+func (m *measured) GetThreadVotes(ctx context.Context, input models.ThreadGetVotes) (votes []models.VoteRecord, err error) {
+	defer m.queries.Observe("vote", "GetThreadVotes", time.Now(), &err)
+	return m.storage.GetThreadVotes(ctx, input)
+}
+
+func (m *measured) GetUserVotes(ctx context.Context, input models.UserGetVotes) (votes []models.VoteRecord, err error) {
+	defer m.queries.Observe("vote", "GetUserVotes", time.Now(), &err)
+	return m.storage.GetUserVotes(ctx, input)
+}
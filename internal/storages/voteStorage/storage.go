@@ -1,16 +1,27 @@
 package voteStorage
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx"
+	"github.com/pringleskate/tp_db_forum/internal/logger"
 	"github.com/pringleskate/tp_db_forum/internal/models"
 )
 
+/*
+	Casting and retracting a vote are one statement each: the row in votes (post_votes) and the
+	counter it feeds change together, and the counter moves by exactly what the row change means,
+	so concurrent votes, even of the same user, cannot make threads.votes drift from the sum of votes.
+*/
 type Storage interface {
-	CreateVote(vote models.Vote, update bool) (thread models.Thread, err error)
-	CheckDoubleVote(vote models.Vote) (thread models.Thread, err error)
+	CreateVote(ctx context.Context, vote models.Vote) (thread models.Thread, err error)
+	CreatePostVote(ctx context.Context, vote models.Vote) (post models.Post, err error)
+	DeleteVote(ctx context.Context, vote models.Vote) (thread models.Thread, err error)
+	DeletePostVote(ctx context.Context, vote models.Vote) (post models.Post, err error)
+	GetThreadVotes(ctx context.Context, input models.ThreadGetVotes) (votes []models.VoteRecord, err error)
+	GetUserVotes(ctx context.Context, input models.UserGetVotes) (votes []models.VoteRecord, err error)
 }
 
 type storage struct {
@@ -25,125 +36,221 @@ func NewStorage(db *pgx.ConnPool) Storage {
 	}
 }
 
-var (
-	insertVote            = "INSERT INTO votes (user_nick, voice, thread) VALUES ($1, $2, $3) ON CONFLICT ON CONSTRAINT uniq_votes DO UPDATE SET voice = EXCLUDED.voice;"
-	createThreadVotesUp   = "UPDATE threads SET votes = votes + 1 WHERE ID = $1 RETURNING ID, author, created, forum, message, slug, title, votes"
-	createThreadVotesDown = "UPDATE threads SET votes = votes - 1 WHERE ID = $1 RETURNING ID, author, created, forum, message, slug, title, votes"
+/*
+	The upsert only touches the row when there is something to change: a new vote comes back as
+	inserted (xmax = 0) and moves the score by 1, a flipped one by 2, repeating a vote returns
+	nothing and moves it by 0. ON CONFLICT locks the existing row and rechecks the WHERE against
+	its latest version, so two requests of the same user are applied one after the other.
+	Nothing is written for archived threads, the caller learns about it from the returned flag.
+*/
+const castVote = `
+	WITH cast_vote AS (
+		INSERT INTO votes AS v (user_nick, voice, thread)
+		SELECT $1, $2, t.ID
+		FROM threads t
+		WHERE t.ID = $3 AND NOT t.archived
+		ON CONFLICT ON CONSTRAINT uniq_votes DO UPDATE SET voice = EXCLUDED.voice
+		WHERE v.voice <> EXCLUDED.voice
+		RETURNING CASE WHEN v.xmax = 0 THEN 1 ELSE 2 END * CASE WHEN v.voice THEN 1 ELSE -1 END AS delta
+	)
+	UPDATE threads
+	SET votes = votes + COALESCE((SELECT delta FROM cast_vote), 0)
+	WHERE ID = $3
+	RETURNING ID, author, created, forum, message, slug, title, votes, archived
+`
 
-	updateThreadVotesUp   = "UPDATE threads SET votes = votes + 2 WHERE ID = $1 RETURNING ID, author, created, forum, message, slug, title, votes"
-	updateThreadVotesDown = "UPDATE threads SET votes = votes - 2 WHERE ID = $1 RETURNING ID, author, created, forum, message, slug, title, votes"
-)
+/* the score moves back by whatever the removed vote added */
+const retractVote = `
+	WITH removed AS (
+		DELETE FROM votes v
+		USING threads t
+		WHERE v.user_nick = $1 AND v.thread = $2 AND t.ID = v.thread AND NOT t.archived
+		RETURNING CASE WHEN v.voice THEN 1 ELSE -1 END AS voice
+	)
+	UPDATE threads
+	SET votes = votes - COALESCE((SELECT voice FROM removed), 0)
+	WHERE ID = $2
+	RETURNING ID, author, created, forum, message, slug, title, votes, archived
+`
 
-func (s *storage) CreateVote(vote models.Vote, update bool) (thread models.Thread, err error) {
-	boolVoice := getBoolVoice(vote)
-	tx, err := s.db.Begin()
-	if err != nil {
-		fmt.Println("txerr", err)
-		return thread, models.Error{Code: "500"}
-	}
+/* same as castVote, deleted posts take no votes either */
+const castPostVote = `
+	WITH cast_vote AS (
+		INSERT INTO post_votes AS v (user_nick, voice, post)
+		SELECT $1, $2, p.ID
+		FROM posts p
+		JOIN threads t ON t.ID = p.thread
+		WHERE p.ID = $3 AND NOT p.deleted AND NOT t.archived
+		ON CONFLICT ON CONSTRAINT uniq_post_votes DO UPDATE SET voice = EXCLUDED.voice
+		WHERE v.voice <> EXCLUDED.voice
+		RETURNING CASE WHEN v.xmax = 0 THEN 1 ELSE 2 END * CASE WHEN v.voice THEN 1 ELSE -1 END AS delta
+	)
+	UPDATE posts p
+	SET votes = p.votes + COALESCE((SELECT delta FROM cast_vote), 0)
+	FROM threads t
+	WHERE p.ID = $3 AND t.ID = p.thread
+	RETURNING p.author, p.created, p.forum, p.message, p.ID, p.edited, p.deleted, p.parent, p.thread, p.votes, t.archived
+`
 
-	_, err = tx.Exec("SET LOCAL synchronous_commit TO OFF")
+const retractPostVote = `
+	WITH removed AS (
+		DELETE FROM post_votes v
+		USING posts p, threads t
+		WHERE v.user_nick = $1 AND v.post = $2 AND p.ID = v.post AND t.ID = p.thread AND NOT t.archived
+		RETURNING CASE WHEN v.voice THEN 1 ELSE -1 END AS voice
+	)
+	UPDATE posts p
+	SET votes = p.votes - COALESCE((SELECT voice FROM removed), 0)
+	FROM threads t
+	WHERE p.ID = $2 AND t.ID = p.thread
+	RETURNING p.author, p.created, p.forum, p.message, p.ID, p.edited, p.deleted, p.parent, p.thread, p.votes, t.archived
+`
+
+var errThreadArchived = models.ErrConflict.WithCode("thread_archived").WithMessage("thread is archived")
+
+func (s *storage) CreateVote(ctx context.Context, vote models.Vote) (thread models.Thread, err error) {
+	thread, err = s.threadVote(ctx, "voteStorage.CreateVote", castVote, vote.User, vote.Voice == 1, vote.Thread.ThreadID)
 	if err != nil {
-		if txErr := tx.Rollback(); txErr != nil {
-			fmt.Println(txErr)
-			return thread, models.Error{Code: "500"}
-		}
-		return thread, models.Error{Code: "500"}
+		return thread, err
 	}
+	if thread.IsArchived {
+		return models.Thread{}, errThreadArchived
+	}
+	return thread, nil
+}
 
-	_, err = tx.Exec(insertVote, vote.User, boolVoice, vote.Thread.ThreadID)
+/* retracting a vote that does not exist leaves the thread as it is */
+func (s *storage) DeleteVote(ctx context.Context, vote models.Vote) (thread models.Thread, err error) {
+	thread, err = s.threadVote(ctx, "voteStorage.DeleteVote", retractVote, vote.User, vote.Thread.ThreadID)
 	if err != nil {
-		if txErr := tx.Rollback(); txErr != nil {
-			fmt.Println(txErr)
-			return thread, models.Error{Code: "500"}
-		}
-		if pqErr, ok := err.(pgx.PgError); ok {
-			switch pqErr.Code {
-			case pgerrcode.ForeignKeyViolation:
-				return thread, models.Error{Code: "404"}
-			default:
-				return thread, models.Error{Code: "500"}
-			}
-		}
-		return thread, models.Error{Code: "500"}
+		return thread, err
 	}
-
-	slug := sql.NullString{}
-
-	if update {
-		if boolVoice {
-			err = tx.QueryRow(updateThreadVotesUp, vote.Thread.ThreadID).
-				Scan(&thread.ID, &thread.Author, &thread.Created, &thread.Forum, &thread.Message, &slug, &thread.Title, &thread.Votes)
-		} else {
-			err = tx.QueryRow(updateThreadVotesDown, vote.Thread.ThreadID).
-				Scan(&thread.ID, &thread.Author, &thread.Created, &thread.Forum, &thread.Message, &slug, &thread.Title, &thread.Votes)
-		}
-	} else {
-		if boolVoice {
-			err = tx.QueryRow(createThreadVotesUp, vote.Thread.ThreadID).
-				Scan(&thread.ID, &thread.Author, &thread.Created, &thread.Forum, &thread.Message, &slug, &thread.Title, &thread.Votes)
-		} else {
-			err = tx.QueryRow(createThreadVotesDown, vote.Thread.ThreadID).
-				Scan(&thread.ID, &thread.Author, &thread.Created, &thread.Forum, &thread.Message, &slug, &thread.Title, &thread.Votes)
-		}
+	if thread.IsArchived {
+		return models.Thread{}, errThreadArchived
 	}
+	return thread, nil
+}
 
+func (s *storage) threadVote(ctx context.Context, op string, query string, args ...interface{}) (thread models.Thread, err error) {
+	slug := sql.NullString{}
+	err = s.db.QueryRowEx(ctx, query, nil, args...).
+		Scan(&thread.ID, &thread.Author, &thread.Created, &thread.Forum, &thread.Message, &slug, &thread.Title, &thread.Votes, &thread.IsArchived)
 	if err != nil {
-		if txErr := tx.Rollback(); txErr != nil {
-			fmt.Println(txErr)
-			return thread, models.Error{Code: "500"}
-		}
-
-		return thread, models.Error{Code: "500"}
+		return thread, voteError(ctx, op, err)
 	}
-
 	if slug.Valid {
 		thread.Slug = slug.String
 	}
+	return thread, nil
+}
 
-	if commitErr := tx.Commit(); commitErr != nil {
-		fmt.Println(commitErr)
-		return thread, models.Error{Code: "500"}
+func (s *storage) CreatePostVote(ctx context.Context, vote models.Vote) (post models.Post, err error) {
+	post, archived, err := s.postVote(ctx, "voteStorage.CreatePostVote", castPostVote, vote.User, vote.Voice == 1, vote.Post)
+	if err != nil {
+		return post, err
 	}
-
-	return
+	if archived {
+		return models.Post{}, errThreadArchived
+	}
+	if post.IsDeleted {
+		return models.Post{}, models.ErrConflict.WithMessage("post is deleted")
+	}
+	return post, nil
 }
 
-func getBoolVoice(vote models.Vote) bool {
-	if vote.Voice == 1 {
-		return true
+func (s *storage) DeletePostVote(ctx context.Context, vote models.Vote) (post models.Post, err error) {
+	post, archived, err := s.postVote(ctx, "voteStorage.DeletePostVote", retractPostVote, vote.User, vote.Post)
+	if err != nil {
+		return post, err
+	}
+	if archived {
+		return models.Post{}, errThreadArchived
 	}
-	return false
+	return post, nil
 }
 
-func (s *storage) CheckDoubleVote(vote models.Vote) (thread models.Thread, err error) {
-	boolVoice := getBoolVoice(vote)
-	var oldVoice bool
-	err = s.db.QueryRow("SELECT voice FROM votes WHERE user_nick = $1 AND thread = $2", vote.User, vote.Thread.ThreadID).
-				Scan(&oldVoice)
+func (s *storage) postVote(ctx context.Context, op string, query string, args ...interface{}) (post models.Post, archived bool, err error) {
+	err = s.db.QueryRowEx(ctx, query, nil, args...).
+		Scan(&post.Author, &post.Created, &post.Forum, &post.Message, &post.ID, &post.IsEdited, &post.IsDeleted, &post.Parent, &post.ThreadInput.ThreadID, &post.Votes, &archived)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			return thread, nil
-		}
-		return thread, models.Error{Code: "500"}
+		return post, archived, voteError(ctx, op, err)
 	}
+	return post, archived, nil
+}
 
-	if oldVoice != boolVoice {
-		return thread, models.Error{Code: "101"}
+/* no row means the thread or post is gone, a foreign key violation that the voter is */
+func voteError(ctx context.Context, op string, err error) error {
+	if err == pgx.ErrNoRows {
+		return models.ErrNotFound
+	}
+	if pqErr, ok := err.(pgx.PgError); ok && pqErr.Code == pgerrcode.ForeignKeyViolation {
+		return models.ErrNotFound.WithMessage("cannot find user")
 	}
+	logger.FromContext(ctx).Error("query failed", "op", op, "err", err)
+	return models.ErrInternal.Wrap(err)
+}
 
-	slug := sql.NullString{}
-	err = s.db.QueryRow("SELECT ID, author, created, forum, message, slug, title, votes FROM threads WHERE ID = $1", vote.Thread.ThreadID).
-				Scan(&thread.ID, &thread.Author, &thread.Created, &thread.Forum, &thread.Message, &slug, &thread.Title, &thread.Votes)
+/* %[1]s compares with since, %[2]s is the order */
+const selectThreadVotes = `
+	SELECT user_nick, thread, voice
+	FROM votes
+	WHERE thread = $1 AND ($2::text = '' OR user_nick %[1]s $2::citext)
+	ORDER BY user_nick %[2]s
+	LIMIT $3
+`
 
-	if slug.Valid {
-		thread.Slug = slug.String
+const selectUserVotes = `
+	SELECT user_nick, thread, voice
+	FROM votes
+	WHERE user_nick = $1 AND ($2 = 0 OR thread %[1]s $2)
+	ORDER BY thread %[2]s
+	LIMIT $3
+`
+
+func (s *storage) GetThreadVotes(ctx context.Context, input models.ThreadGetVotes) (votes []models.VoteRecord, err error) {
+	query := fmt.Sprintf(selectThreadVotes, ">", "ASC")
+	if input.Desc {
+		query = fmt.Sprintf(selectThreadVotes, "<", "DESC")
 	}
 
+	rows, err := s.db.QueryEx(ctx, query, nil, input.ThreadID, input.Since, input.Limit)
 	if err != nil {
-		return thread, models.Error{Code: "500"}
+		return nil, models.ErrInternal.Wrap(err)
 	}
+	return scanVotes(rows)
+}
 
-	return thread, models.Error{Code: "409"}
+func (s *storage) GetUserVotes(ctx context.Context, input models.UserGetVotes) (votes []models.VoteRecord, err error) {
+	query := fmt.Sprintf(selectUserVotes, ">", "ASC")
+	if input.Desc {
+		query = fmt.Sprintf(selectUserVotes, "<", "DESC")
+	}
+
+	rows, err := s.db.QueryEx(ctx, query, nil, input.Nickname, input.Since, input.Limit)
+	if err != nil {
+		return nil, models.ErrInternal.Wrap(err)
+	}
+	return scanVotes(rows)
 }
 
+func scanVotes(rows *pgx.Rows) (votes []models.VoteRecord, err error) {
+	defer rows.Close()
+
+	votes = make([]models.VoteRecord, 0)
+	for rows.Next() {
+		vote := models.VoteRecord{Voice: -1}
+		var voice bool
+		if err = rows.Scan(&vote.User, &vote.Thread, &voice); err != nil {
+			return nil, models.ErrInternal.Wrap(err)
+		}
+		if voice {
+			vote.Voice = 1
+		}
+		votes = append(votes, vote)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, models.ErrInternal.Wrap(err)
+	}
+
+	return votes, nil
+}
@@ -0,0 +1,46 @@
+package voteStorage_test
+
+import (
+	"github.com/pringleskate/tp_db_forum/internal/models"
+	"github.com/pringleskate/tp_db_forum/internal/storages/voteStorage"
+	"github.com/pringleskate/tp_db_forum/internal/storages/voteStorage/votetest"
+	"github.com/pringleskate/tp_db_forum/internal/testdb"
+	"testing"
+)
+
+/*
+	The upsert and retract statements keep threads.votes and posts.votes at the sum over their votes.
+	FORUM_TEST_DSN=... go test -race -run ConcurrentVotes ./internal/storages/voteStorage
+*/
+func TestConcurrentVotes(t *testing.T) {
+	db := testdb.Open(t)
+	users := []string{"alice", "bob", "carol", "dave", "eve", "frank", "grace", "heidi"}
+	thread := testdb.Seed(t, db, users...)
+
+	var post int
+	err := db.QueryRow("INSERT INTO posts (author, created, forum, message, thread) VALUES ('alice', now(), 'f', 'm', $1) RETURNING ID", thread).Scan(&post)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	votetest.Hammer(t, voteStorage.NewStorage(db), models.ThreadInput{ThreadID: thread}, post, users)
+
+	var votes, sum int
+	err = db.QueryRow("SELECT votes, (SELECT COALESCE(SUM(CASE WHEN voice THEN 1 ELSE -1 END), 0) FROM votes WHERE thread = $1) FROM threads WHERE ID = $1", thread).
+		Scan(&votes, &sum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if votes != sum {
+		t.Errorf("threads.votes %d, sum over votes %d", votes, sum)
+	}
+
+	err = db.QueryRow("SELECT votes, (SELECT COALESCE(SUM(CASE WHEN voice THEN 1 ELSE -1 END), 0) FROM post_votes WHERE post = $1) FROM posts WHERE ID = $1", post).
+		Scan(&votes, &sum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if votes != sum {
+		t.Errorf("posts.votes %d, sum over post_votes %d", votes, sum)
+	}
+}
@@ -0,0 +1,64 @@
+package votetest
+
+import (
+	"context"
+	"github.com/pringleskate/tp_db_forum/internal/models"
+	"github.com/pringleskate/tp_db_forum/internal/storages/voteStorage"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+/* goroutines per user, so the same user's votes race each other as well as other users' */
+const workers = 2
+
+const rounds = 200
+
+/*
+	Hammer casts, flips and retracts votes of every user on the thread and the post from
+	concurrent goroutines. Afterwards the scores have to equal the sum over the stored votes,
+	checking that is up to the caller, who knows where the backend keeps them.
+*/
+func Hammer(tb testing.TB, votes voteStorage.Storage, thread models.ThreadInput, post int, users []string) {
+	tb.Helper()
+
+	ctx := context.Background()
+	errs := make(chan error, len(users)*workers)
+	wg := sync.WaitGroup{}
+
+	for i, user := range users {
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func(user string, seed int64) {
+				defer wg.Done()
+
+				random := rand.New(rand.NewSource(seed))
+				for r := 0; r < rounds; r++ {
+					vote := models.Vote{User: user, Voice: []int{-1, 1}[random.Intn(2)], Thread: thread, Post: post}
+
+					var err error
+					switch random.Intn(6) {
+					case 0, 1:
+						_, err = votes.CreateVote(ctx, vote)
+					case 2:
+						_, err = votes.DeleteVote(ctx, vote)
+					case 3, 4:
+						_, err = votes.CreatePostVote(ctx, vote)
+					case 5:
+						_, err = votes.DeletePostVote(ctx, vote)
+					}
+					if err != nil {
+						errs <- err
+						return
+					}
+				}
+			}(user, int64(i*workers+w))
+		}
+	}
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		tb.Error(err)
+	}
+}
@@ -0,0 +1,68 @@
+package testdb
+
+import (
+	"github.com/jackc/pgx"
+	"github.com/pringleskate/tp_db_forum/internal/migrations"
+	"os"
+	"testing"
+)
+
+/* names a database the storage tests may migrate and wipe, without it they are skipped */
+const EnvDSN = "FORUM_TEST_DSN"
+
+/* a pool on the test database with the schema up to date and every table empty */
+func Open(tb testing.TB) *pgx.ConnPool {
+	tb.Helper()
+
+	dsn := os.Getenv(EnvDSN)
+	if dsn == "" {
+		tb.Skipf("%s is not set", EnvDSN)
+	}
+
+	connConfig, err := pgx.ParseConnectionString(dsn)
+	if err != nil {
+		tb.Fatalf("%s: %v", EnvDSN, err)
+	}
+	db, err := pgx.NewConnPool(pgx.ConnPoolConfig{ConnConfig: connConfig, MaxConnections: 20})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(db.Close)
+
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if _, err = migrator.Up(); err != nil {
+		tb.Fatal(err)
+	}
+
+	Exec(tb, db, "TRUNCATE users, forums, threads, posts, forum_users, votes, post_votes, post_revisions RESTART IDENTITY CASCADE")
+	Exec(tb, db, "ALTER SEQUENCE post_id_seq RESTART")
+	return db
+}
+
+/* users with the given nicknames, forum f owned by the first of them and thread 1 in it */
+func Seed(tb testing.TB, db *pgx.ConnPool, nicknames ...string) (thread int) {
+	tb.Helper()
+
+	for _, nickname := range nicknames {
+		Exec(tb, db, "INSERT INTO users (nickname, fullname, email) VALUES ($1, $1, $1 || '@test')", nickname)
+	}
+	Exec(tb, db, "INSERT INTO forums (slug, title, user_nick) VALUES ('f', 'f', $1)", nicknames[0])
+	Exec(tb, db, "UPDATE forums SET threads = 1 WHERE slug = 'f'")
+
+	err := db.QueryRow("INSERT INTO threads (author, forum, message, title) VALUES ($1, 'f', 'm', 't') RETURNING ID", nicknames[0]).Scan(&thread)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return thread
+}
+
+func Exec(tb testing.TB, db *pgx.ConnPool, sql string, args ...interface{}) {
+	tb.Helper()
+
+	if _, err := db.Exec(sql, args...); err != nil {
+		tb.Fatalf("%s: %v", sql, err)
+	}
+}